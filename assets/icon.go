@@ -0,0 +1,83 @@
+package assets
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/adrg/xdg"
+	"github.com/markbates/pkger"
+)
+
+// PNGIcon returns the name of the PNG icon from the
+// icon, as the icons on the resources are referenced
+// as '.svg' but we only have the '.png' version
+func PNGIcon(icon string) string {
+	ext := path.Ext(icon)
+	return fmt.Sprintf("%s.png", icon[0:len(icon)-len(ext)])
+}
+
+// Icon returns the PNG content of the icon of the
+// provider pv (ex: 'aws')
+func Icon(pv, icon string) ([]byte, error) {
+	f, err := pkger.Open(path.Join("/assets", "icons", pv, PNGIcon(icon)))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ioutil.ReadAll(f)
+}
+
+// IconBase64 returns the PNG content of the icon of the
+// provider pv encoded in base64 so it can be used
+// to embed it in other formats
+func IconBase64(pv, icon string) (string, error) {
+	b, err := Icon(pv, icon)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// CacheIcon returns the path of the PNG icon of the provider pv
+// on the XDG cache directory, writing it if it's not already there,
+// so it can be referenced by formats that need a file
+func CacheIcon(pv, icon string) (string, error) {
+	pngIcon := PNGIcon(icon)
+	assetPath := path.Join("inframap", "assets", pv, pngIcon)
+	pathIcon := path.Join(xdg.CacheHome, assetPath)
+
+	// If the file does not exists on the Cache path, we have to write it,
+	// if not it means it's already correct so nothing to be done
+	if _, err := os.Stat(pathIcon); !os.IsNotExist(err) {
+		return pathIcon, nil
+	}
+
+	p, err := xdg.CacheFile(assetPath)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.Create(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	iconFile, err := pkger.Open(path.Join("/assets", "icons", pv, pngIcon))
+	if err != nil {
+		return "", err
+	}
+	defer iconFile.Close()
+
+	if _, err = io.Copy(f, iconFile); err != nil {
+		return "", err
+	}
+
+	return pathIcon, nil
+}
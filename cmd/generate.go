@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"strings"
 
@@ -20,13 +22,22 @@ var (
 	connections   bool
 	showIcons     bool
 	externalNodes bool
+	plainASCII    bool
+	width         int
+	stackName     string
+	themePath     string
+	labelTemplate string
+	attributes    []string
+	instances     string
+	vars          []string
+	varFiles      []string
 
 	generateCmd = &cobra.Command{
-		Use:     "generate [FILE]",
+		Use:     "generate [FILE...]",
 		Short:   "Generates the Graph",
-		Long:    "Generates the Graph from TFState or HCL",
-		Example: "inframap generate state.tfstate\ncat state.tfstate | inframap generate",
-		Args:    cobra.MaximumNArgs(1),
+		Long:    "Generates the Graph from TFState, HCL, the JSON of 'terraform show -json' of a plan or the JSON of 'pulumi stack export'. With more than one TFState, or a directory of them, each one is a stack of the same Graph",
+		Example: "inframap generate state.tfstate\ncat state.tfstate | inframap generate\nterraform show -json plan.out | inframap generate\npulumi stack export | inframap generate\ninframap generate network.tfstate app.tfstate",
+		Args:    cobra.ArbitraryArgs,
 		PreRunE: preRunFile,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opt := generate.Options{
@@ -34,6 +45,26 @@ var (
 				Clean:         clean,
 				Connections:   connections,
 				ExternalNodes: externalNodes,
+				Attributes:    append(attributes, printer.LabelAttributes(labelTemplate)...),
+				Instances:     generate.InstancesMode(instances),
+				VarFiles:      varFiles,
+			}
+
+			if len(vars) != 0 {
+				opt.Vars = make(map[string]string, len(vars))
+				for _, v := range vars {
+					kv := strings.SplitN(v, "=", 2)
+					if len(kv) != 2 || kv[0] == "" {
+						return fmt.Errorf("invalid var %q, the format is 'name=value'", v)
+					}
+					opt.Vars[kv[0]] = kv[1]
+				}
+			}
+
+			switch opt.Instances {
+			case generate.InstancesFirst, generate.InstancesExpand, generate.InstancesCollapse:
+			default:
+				return fmt.Errorf("invalid instances %q, the supported ones are: %s", instances, strings.Join(generate.InstancesModeStrings(), ","))
 			}
 
 			var (
@@ -41,27 +72,25 @@ var (
 				err error
 			)
 
-			if tfstate {
+			if len(stacks) != 0 {
+				g, _, err = generate.FromStates(stacks, opt)
+			} else if tfstate {
 				g, _, err = generate.FromState(file, opt)
+			} else if tfplan {
+				g, err = generate.FromPlan(file, opt)
+			} else if pulumi {
+				g, _, err = generate.FromPulumi(file, opt)
 			} else {
 				if len(file) == 0 {
 					g, err = generate.FromHCL(afero.NewOsFs(), path, opt)
 				} else {
-					fs := afero.NewMemMapFs()
 					path = "module.tf"
-
-					var f afero.File
-					f, err = fs.Create(path)
-					if err != nil {
-						return err
-					}
-
-					_, err = f.Write(file)
-					if err != nil {
-						return err
+					if json.Valid(file) {
+						path = "module.tf.json"
 					}
 
-					err = f.Sync()
+					var fs afero.Fs
+					fs, err = stdinFs(path, file, varFiles)
 					if err != nil {
 						return err
 					}
@@ -80,8 +109,25 @@ var (
 			}
 
 			popt := printer.Options{
-				ShowIcons: showIcons,
+				ShowIcons:     showIcons,
+				PlainASCII:    plainASCII,
+				Width:         width,
+				StackName:     stackName,
+				LabelTemplate: labelTemplate,
 			}
+
+			if themePath != "" {
+				b, err := afero.ReadFile(afero.NewOsFs(), themePath)
+				if err != nil {
+					return err
+				}
+
+				popt.Theme, err = printer.ParseTheme(b)
+				if err != nil {
+					return err
+				}
+			}
+
 			err = p.Print(g, popt, os.Stdout)
 			if err != nil {
 				return err
@@ -92,6 +138,28 @@ var (
 	}
 )
 
+// stdinFs returns a memory Fs with only the file of the STDIN on the path
+// and the varFiles copied from the OS, so nothing else of the working
+// dir (like the 'terraform.tfvars') is read as part of the STDIN
+func stdinFs(path string, file []byte, varFiles []string) (afero.Fs, error) {
+	fs := afero.NewMemMapFs()
+	if err := afero.WriteFile(fs, path, file, 0644); err != nil {
+		return nil, err
+	}
+
+	for _, vf := range varFiles {
+		b, err := ioutil.ReadFile(vf)
+		if err != nil {
+			return nil, err
+		}
+		if err := afero.WriteFile(fs, vf, b, 0644); err != nil {
+			return nil, err
+		}
+	}
+
+	return fs, nil
+}
+
 func init() {
 	generateCmd.Flags().StringVar(&printerType, "printer", "dot", fmt.Sprintf("Type of printer to use for the output. Supported ones are: %s", strings.Join(printer.TypeStrings(), ",")))
 	generateCmd.Flags().BoolVar(&raw, "raw", false, "Raw will not use any specific logic from the provider, will just display the connections between elements. It's used by default if none of the Providers is known")
@@ -99,4 +167,13 @@ func init() {
 	generateCmd.Flags().BoolVar(&connections, "connections", true, "Connections will apply the logic of the provider to remove resources that are not nodes")
 	generateCmd.Flags().BoolVar(&showIcons, "show-icons", true, "Toggle the icons on the printed graph")
 	generateCmd.Flags().BoolVar(&externalNodes, "external-nodes", true, "Toggle the addition of external nodes like 'im_out' (used to show ingress connections)")
+	generateCmd.Flags().BoolVar(&plainASCII, "plain-ascii", false, "Use only ASCII characters on the text printers (like 'ascii'), useful for logs")
+	generateCmd.Flags().IntVar(&width, "width", 0, "Maximum number of columns of the text printers (like 'ascii'), wider outputs are split in pages. If 0 the default of the printer is used")
+	generateCmd.Flags().StringVar(&stackName, "stack-name", "default", "Name of the stack used by the printers that can hold more than one (like 'cypher') to identify its resources")
+	generateCmd.Flags().StringVar(&themePath, "theme", "", "Path to a YAML or JSON file with the theme (colors, shapes, fonts, legend ...) used by the printers that support it (like 'dot')")
+	generateCmd.Flags().StringVar(&labelTemplate, "label-template", "", "Go text/template used for the label of the Nodes, ex: '{{.Name}} ({{.Type}})' or '{{.Attr \"tags.Name\" | default .Canonical}}'. The attributes used with '.Attr' are kept automatically")
+	generateCmd.Flags().StringSliceVar(&attributes, "attributes", nil, "List of attributes (ex: 'tags.Name') of the resources to keep on the Nodes")
+	generateCmd.Flags().StringVar(&instances, "instances", string(generate.InstancesFirst), fmt.Sprintf("How the instances of the resources with 'count' or 'for_each' are shown on a TFState or plan, only the first one, one Node per instance or one Node with the number of instances. Supported ones are: %s", strings.Join(generate.InstancesModeStrings(), ",")))
+	generateCmd.Flags().StringArrayVar(&vars, "var", nil, "Value of a variable of the HCL with the format 'name=value', like the '-var' of Terraform. Can be used multiple times")
+	generateCmd.Flags().StringArrayVar(&varFiles, "var-file", nil, "Path to a '.tfvars' file with the values of the variables of the HCL, like the '-var-file' of Terraform. The 'terraform.tfvars' and '*.auto.tfvars' of the directory are always read. Can be used multiple times")
 }
@@ -18,13 +18,19 @@ var (
 		Args:    cobra.MaximumNArgs(1),
 		PreRunE: preRunFile,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if tfstate {
+			if len(stacks) != 0 {
+				return errors.New("prune does not support multiple stacks, prune each TFState")
+			} else if tfstate {
 				s, err := prune.Prune(file, canonicals)
 				if err != nil {
 					return err
 				}
 
 				fmt.Println(string(s))
+			} else if tfplan {
+				return errors.New("prune does not support plans yet")
+			} else if pulumi {
+				return errors.New("prune does not support Pulumi yet")
 			} else {
 				return errors.New("prune does not support HCL yet")
 			}
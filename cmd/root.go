@@ -3,18 +3,35 @@ package cmd
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 
+	"github.com/cycloidio/inframap/generate"
+	"github.com/cycloidio/inframap/state"
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 )
 
 var (
 	hcl     bool
 	tfstate bool
+	tfplan  bool
+	pulumi  bool
 	file    []byte
 	path    string
 
+	// workspace is the one used to read the
+	// TFState of a Terraform working directory
+	workspace string
+
+	// stacks holds the TFState of each stack
+	// when more than one is used
+	stacks map[string]json.RawMessage
+
 	rootCmd = &cobra.Command{
 		Use:   "inframap",
 		Short: "Reads the TFState or HCL to generate a Graphical view",
@@ -33,6 +50,13 @@ func Execute() error {
 func preRunFile(cmd *cobra.Command, args []string) error {
 	var err error
 
+	if len(args) > 1 {
+		if hcl || tfplan || pulumi {
+			return errors.New("more than one file can only be used with TFStates, each one is read as a stack")
+		}
+		return readStacks(args)
+	}
+
 	if len(args) == 1 {
 		path = args[0]
 
@@ -47,9 +71,48 @@ func preRunFile(cmd *cobra.Command, args []string) error {
 				return err
 			}
 		} else {
-			// Only HCL can used with dirs
+			// The dirs are HCL except if they only
+			// have TFStates, then each one is a stack
+			// if there is more than one, or if it's
+			// asked to read the TFState from the backend
+			// of the Terraform working dir with --tfstate
+			// or --workspace
+			states, err := filepath.Glob(filepath.Join(path, "*.tfstate"))
+			if err != nil {
+				return err
+			}
+			if !hcl && (workspace != "" || (tfstate && hasConfigFiles(path))) {
+				file, err = readWorkingDir(path)
+				if err != nil {
+					return err
+				}
+
+				hcl = false
+				tfstate = true
+				tfplan = false
+
+				return nil
+			}
+			if len(states) > 1 && !hcl && !hasConfigFiles(path) {
+				return readStacks(states)
+			}
+			if len(states) == 1 && !hcl && !hasConfigFiles(path) {
+				file, err = ioutil.ReadFile(states[0])
+				if err != nil {
+					return err
+				}
+
+				hcl = false
+				tfstate = true
+				tfplan = false
+				pulumi = false
+
+				return nil
+			}
+
 			hcl = true
 			tfstate = false
+			tfplan = false
 		}
 	} else {
 		fi, err := os.Stdin.Stat()
@@ -73,12 +136,81 @@ func preRunFile(cmd *cobra.Command, args []string) error {
 	return err
 }
 
-// setGenerateType will try to guess the file content by first parsing it in JSON
-// and if it fails fallback to HCL.
-// If any of the flags --hcl or --tfstate are set it'll do nothing and use those
-// directly as they are setted by the user
+// reInvalidStackName matches the characters that can not be
+// on the name of a stack, as it's part of the Canonicals
+var reInvalidStackName = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// readStacks reads the TFState on each of the paths as a stack, the name of the stack
+// is the name of the file without the extensions ('.tfstate' and '.backup') or the
+// directory it's in if it's the default 'terraform.tfstate', with the characters
+// that can not be on the name of a stack replaced ('prod.network' to 'prod_network')
+func readStacks(paths []string) error {
+	stacks = make(map[string]json.RawMessage, len(paths))
+	for _, p := range paths {
+		// The path is absolute so the dir of the
+		// './terraform.tfstate' is not '.'
+		ap, err := filepath.Abs(p)
+		if err != nil {
+			return err
+		}
+
+		name := strings.TrimSuffix(filepath.Base(ap), ".backup")
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+		if name == "terraform" {
+			name = filepath.Base(filepath.Dir(ap))
+		}
+		name = reInvalidStackName.ReplaceAllString(name, "_")
+		if _, ok := stacks[name]; ok {
+			return fmt.Errorf("the stack %q of %q is repeated", name, p)
+		}
+
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		stacks[name] = b
+	}
+
+	hcl = false
+	tfstate = true
+	tfplan = false
+	pulumi = false
+
+	return nil
+}
+
+// readWorkingDir reads the TFState of the Terraform working dir from its
+// backend, of the --workspace, the TF_WORKSPACE or the selected one
+func readWorkingDir(dir string) ([]byte, error) {
+	ws := workspace
+	if ws == "" {
+		ws = os.Getenv("TF_WORKSPACE")
+	}
+
+	return state.ReadDir(afero.NewOsFs(), dir, ws, map[string]state.Reader{
+		state.BackendS3: state.S3Reader{},
+	})
+}
+
+// hasConfigFiles checks if the dir has
+// any Terraform configuration file
+func hasConfigFiles(dir string) bool {
+	for _, ext := range []string{"*.tf", "*.tf.json"} {
+		if fs, _ := filepath.Glob(filepath.Join(dir, ext)); len(fs) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// setGenerateType will try to guess the file content by first parsing it in JSON,
+// if it's a configuration in JSON ('*.tf.json') it's HCL, if it's the output of
+// 'terraform show -json' it's a plan, if it's the output of 'pulumi stack export'
+// it's Pulumi, if not a TFState, and if it fails fallback to HCL.
+// If any of the flags --hcl, --tfstate, --tfplan or --pulumi are set it'll do nothing
+// and use those directly as they are setted by the user
 func setGenerateType(b []byte) {
-	if hcl || tfstate {
+	if hcl || tfstate || tfplan || pulumi {
 		return
 	}
 
@@ -86,12 +218,33 @@ func setGenerateType(b []byte) {
 	if err := json.Unmarshal(b, &aux); err != nil {
 		hcl = true
 		tfstate = false
+	} else if isJSONConfig(aux) {
+		hcl = true
+		tfstate = false
+	} else if generate.IsPlan(b) {
+		hcl = false
+		tfplan = true
+	} else if generate.IsPulumi(b) {
+		hcl = false
+		pulumi = true
 	} else {
 		hcl = false
 		tfstate = true
 	}
 }
 
+// isJSONConfig checks if the aux is a Terraform configuration
+// written in JSON ('*.tf.json'), which has the blocks
+// like 'resource' or 'module' on the top level
+func isJSONConfig(aux map[string]interface{}) bool {
+	for _, k := range []string{"resource", "module", "terraform", "provider"} {
+		if _, ok := aux[k]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 func init() {
 	rootCmd.AddCommand(
 		generateCmd,
@@ -101,4 +254,7 @@ func init() {
 
 	rootCmd.PersistentFlags().BoolVar(&hcl, "hcl", false, "Forces to use HCL parser")
 	rootCmd.PersistentFlags().BoolVar(&tfstate, "tfstate", false, "Forces to use TFState parser")
+	rootCmd.PersistentFlags().StringVar(&workspace, "workspace", "", "Workspace of the Terraform working directory from which the TFState is read, if not set the TF_WORKSPACE or the selected one is used")
+	rootCmd.PersistentFlags().BoolVar(&tfplan, "tfplan", false, "Forces to use the parser of the JSON of 'terraform show -json' of a plan or a state")
+	rootCmd.PersistentFlags().BoolVar(&pulumi, "pulumi", false, "Forces to use the parser of the JSON of 'pulumi stack export'")
 }
@@ -18,6 +18,10 @@ var (
 	ErrGraphAlreadyExistsNodeID      = errors.New("graph node ID already exists")
 	ErrGraphNotFoundNode             = errors.New("graph node not found")
 	ErrGraphRequiredEdgeBetweenNodes = errors.New("graph requires edge between nodes")
+	ErrGraphRequiredGroupID          = errors.New("graph group ID is required")
+	ErrGraphAlreadyExistsGroup       = errors.New("graph group already exists")
+	ErrGraphNotFoundGroup            = errors.New("graph group not found")
+	ErrGraphNotFoundGroupParent      = errors.New("graph group parent not found")
 
 	ErrProviderNotFoundResource   = errors.New("provider resource not found")
 	ErrProviderNotFoundDataSource = errors.New("provider data source not found")
@@ -26,8 +30,16 @@ var (
 	ErrInvalidTFStateFile                  = errors.New("invalid Terraform State file")
 	ErrInvalidTFStateVersion               = errors.New("invalid Terraform State version, we only support version 3 and 4")
 	ErrInvalidTFStateFileMissingResourceID = errors.New("invalid Terraform State file, a resource is missing the attributes.id")
+	ErrInvalidStackName                    = errors.New("invalid stack name, it's required and it can not have '.'")
+	ErrInvalidTFStateEncrypted             = errors.New("invalid Terraform State file, it's encrypted and it has to be decrypted first")
 
-	ErrPrinterNotFound = errors.New("printer not found")
+	ErrInvalidTFPlanFile = errors.New("invalid Terraform plan JSON file")
 
-	ErrGenerateFromJSON = errors.New("we do not support JSON HCL")
+	ErrInvalidPulumiFile = errors.New("invalid Pulumi stack export JSON file")
+
+	ErrBackendNotSupported = errors.New("backend not supported, the TFState can be read with 'terraform state pull'")
+
+	ErrPrinterNotFound             = errors.New("printer not found")
+	ErrPrinterInvalidTheme         = errors.New("printer invalid theme")
+	ErrPrinterInvalidLabelTemplate = errors.New("printer invalid label template")
 )
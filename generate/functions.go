@@ -0,0 +1,153 @@
+package generate
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"path/filepath"
+	"unicode/utf8"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform/lang"
+	"github.com/spf13/afero"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// functions returns the functions of Terraform that can be used on the
+// expressions of the module on the dir, with the ones that read files
+// ('file', 'templatefile', ...) reading them from the fs instead of
+// from the OS, as the HCL could be from STDIN on a memory fs
+func functions(fs afero.Fs, dir string) map[string]function.Function {
+	funcs := (&lang.Scope{BaseDir: dir, PureOnly: true}).Functions()
+
+	funcs["file"] = makeFileFunc(fs, dir, func(b []byte) (cty.Value, error) {
+		if !utf8.Valid(b) {
+			return cty.UnknownVal(cty.String), fmt.Errorf("contents of the file are not valid UTF-8, use 'filebase64' instead")
+		}
+		return cty.StringVal(string(b)), nil
+	})
+	funcs["filebase64"] = makeFileFunc(fs, dir, func(b []byte) (cty.Value, error) {
+		return cty.StringVal(base64.StdEncoding.EncodeToString(b)), nil
+	})
+	funcs["filemd5"] = makeFileHashFunc(fs, dir, md5.New, hex.EncodeToString)
+	funcs["filesha1"] = makeFileHashFunc(fs, dir, sha1.New, hex.EncodeToString)
+	funcs["filesha256"] = makeFileHashFunc(fs, dir, sha256.New, hex.EncodeToString)
+	funcs["filesha512"] = makeFileHashFunc(fs, dir, sha512.New, hex.EncodeToString)
+	funcs["filebase64sha256"] = makeFileHashFunc(fs, dir, sha256.New, base64.StdEncoding.EncodeToString)
+	funcs["filebase64sha512"] = makeFileHashFunc(fs, dir, sha512.New, base64.StdEncoding.EncodeToString)
+
+	funcs["fileexists"] = function.New(&function.Spec{
+		Params: []function.Parameter{{Name: "path", Type: cty.String}},
+		Type:   function.StaticReturnType(cty.Bool),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			fi, err := fs.Stat(filePath(dir, args[0].AsString()))
+			if err != nil {
+				return cty.False, nil
+			}
+			return cty.BoolVal(fi.Mode().IsRegular()), nil
+		},
+	})
+
+	// The globs of 'fileset' are not supported by
+	// the fs so the files matched are not known
+	funcs["fileset"] = function.New(&function.Spec{
+		Params: []function.Parameter{{Name: "path", Type: cty.String}, {Name: "pattern", Type: cty.String}},
+		Type:   function.StaticReturnType(cty.Set(cty.String)),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			return cty.UnknownVal(retType), nil
+		},
+	})
+
+	funcs["templatefile"] = makeTemplateFileFunc(fs, dir, funcs)
+
+	return funcs
+}
+
+// filePath returns the path relative to the dir
+// of the module if it's not an absolute one
+func filePath(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// makeFileFunc returns a function that reads the file on the
+// path from the fs and returns the value of it with fn
+func makeFileFunc(fs afero.Fs, dir string, fn func(b []byte) (cty.Value, error)) function.Function {
+	return function.New(&function.Spec{
+		Params: []function.Parameter{{Name: "path", Type: cty.String}},
+		Type:   function.StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			b, err := afero.ReadFile(fs, filePath(dir, args[0].AsString()))
+			if err != nil {
+				return cty.UnknownVal(retType), err
+			}
+			return fn(b)
+		},
+	})
+}
+
+// makeFileHashFunc returns a function that reads the file on the path
+// from the fs and returns the hash hf of it encoded with enc
+func makeFileHashFunc(fs afero.Fs, dir string, hf func() hash.Hash, enc func([]byte) string) function.Function {
+	return makeFileFunc(fs, dir, func(b []byte) (cty.Value, error) {
+		h := hf()
+		h.Write(b)
+		return cty.StringVal(enc(h.Sum(nil))), nil
+	})
+}
+
+// makeTemplateFileFunc returns the 'templatefile' function that reads the
+// template from the fs and renders it with the vars and the funcs, on
+// which 'templatefile' can not be used to avoid recursive calls
+func makeTemplateFileFunc(fs afero.Fs, dir string, funcs map[string]function.Function) function.Function {
+	return function.New(&function.Spec{
+		Params: []function.Parameter{{Name: "path", Type: cty.String}, {Name: "vars", Type: cty.DynamicPseudoType}},
+		Type:   function.StaticReturnType(cty.DynamicPseudoType),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			path := args[0].AsString()
+			b, err := afero.ReadFile(fs, filePath(dir, path))
+			if err != nil {
+				return cty.DynamicVal, err
+			}
+
+			vars := args[1]
+			if !vars.IsWhollyKnown() {
+				return cty.DynamicVal, nil
+			}
+			if vt := vars.Type(); !vt.IsMapType() && !vt.IsObjectType() {
+				return cty.DynamicVal, function.NewArgErrorf(1, "invalid vars value: must be a map")
+			}
+
+			expr, diags := hclsyntax.ParseTemplate(b, path, hcl.Pos{Line: 1, Column: 1})
+			if diags.HasErrors() {
+				return cty.DynamicVal, diags
+			}
+
+			tfuncs := make(map[string]function.Function, len(funcs))
+			for n, f := range funcs {
+				if n != "templatefile" {
+					tfuncs[n] = f
+				}
+			}
+
+			val, diags := expr.Value(&hcl.EvalContext{
+				Variables: vars.AsValueMap(),
+				Functions: tfuncs,
+			})
+			if diags.HasErrors() {
+				return cty.DynamicVal, diags
+			}
+
+			return val, nil
+		},
+	})
+}
@@ -3,7 +3,7 @@ package generate
 import (
 	"errors"
 	"fmt"
-	"strings"
+	"path/filepath"
 
 	"github.com/cycloidio/inframap/errcode"
 	"github.com/cycloidio/inframap/graph"
@@ -15,6 +15,7 @@ import (
 	"github.com/hashicorp/terraform/configs/hcl2shim"
 	uuid "github.com/satori/go.uuid"
 	"github.com/spf13/afero"
+	"github.com/zclconf/go-cty/cty"
 )
 
 // FromHCL generates a new graph from the HCL on the path,
@@ -30,7 +31,9 @@ func FromHCL(fs afero.Fs, path string, opt Options) (*graph.Graph, error) {
 		err   error
 	)
 
+	rootDir := filepath.Dir(path)
 	if parser.IsConfigDir(path) {
+		rootDir = path
 		mod, diags = parser.LoadConfigDir(path)
 	} else {
 		f, dgs := parser.LoadConfigFile(path)
@@ -44,6 +47,23 @@ func FromHCL(fs afero.Fs, path string, opt Options) (*graph.Graph, error) {
 		return nil, errors.New(diags.Error())
 	}
 
+	manifest, err := readModulesManifest(fs, rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	root := &hclModule{mod: mod, dir: rootDir}
+	if err := root.loadChildren(parser, "", manifest); err != nil {
+		return nil, err
+	}
+	modules := root.modules()
+
+	vars, err := readRootVars(fs, parser, rootDir, mod, opt)
+	if err != nil {
+		return nil, err
+	}
+	root.buildEvalContext(fs, vars)
+
 	// nodeCanID holds as key the `aws_alb.front` (graph.Node.Canonical)
 	// and as value the UUID (graph.Node.ID) we give to it
 	nodeCanID := make(map[string]string)
@@ -58,78 +78,79 @@ func FromHCL(fs afero.Fs, path string, opt Options) (*graph.Graph, error) {
 	resourcesRawConfig := make(map[string]map[string]interface{})
 
 	if !opt.Raw {
-		opt, err = checkHCLProviders(mod, opt)
+		opt, err = checkHCLProviders(modules, opt)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	for rk, rv := range mod.ManagedResources {
-		pv, rs, err := getProviderAndResource(rk, opt)
-		if err != nil {
-			if errors.Is(err, errcode.ErrProviderNotFound) {
-				continue
+	for _, m := range modules {
+		for rk, rv := range m.mod.ManagedResources {
+			pv, rs, err := getProviderAndResource(rk, opt)
+			if err != nil {
+				if errors.Is(err, errcode.ErrProviderNotFound) {
+					continue
+				}
+				return nil, err
 			}
-			return nil, err
-		}
-
-		// If it's not a Node or Edge we ignore it
-		if !pv.IsNode(rs) && !pv.IsEdge(rs) {
-			continue
-		}
 
-		res, err := pv.Resource(rs)
-		if err != nil {
-			return nil, err
-		}
-		n := &graph.Node{
-			ID:        uuid.NewV4().String(),
-			Canonical: rk,
-			Resource:  *res,
-		}
+			// If it's not a Node or Edge we ignore it
+			if !pv.IsNode(rs) && !pv.IsEdge(rs) {
+				continue
+			}
 
-		err = g.AddNode(n)
-		if err != nil {
-			return nil, err
-		}
+			res, err := pv.Resource(rs)
+			if err != nil {
+				return nil, err
+			}
+			n := &graph.Node{
+				ID:        uuid.NewV4().String(),
+				Canonical: prefixWithModule(m.path, rk),
+				Resource:  *res,
+			}
 
-		nodeCanID[n.Canonical] = n.ID
+			err = g.AddNode(n)
+			if err != nil {
+				return nil, err
+			}
 
-		links := make(map[string][]string)
-		body, ok := rv.Config.(*hclsyntax.Body)
-		if ok {
-			links = getBodyLinks(body)
-			cfg := getBodyJSON(body)
-			cfg[provider.HCLCanonicalKey] = rk
+			nodeCanID[n.Canonical] = n.ID
+
+			var (
+				links   map[string][]string
+				bodyCfg map[string]interface{}
+			)
+			body, ok := rv.Config.(*hclsyntax.Body)
+			if ok {
+				links = getBodyLinks(body)
+				bodyCfg = getBodyJSON(body, m.ctx)
+			} else {
+				// If it's not a hclsyntax.Body it's from a JSON
+				// file ('*.tf.json'), as there is no schema all the
+				// keys, blocks included, are read as attributes
+				attrs, diags := rv.Config.JustAttributes()
+				if diags.HasErrors() {
+					return nil, errors.New(diags.Error())
+				}
+				links = getJSONBodyLinks(attrs)
+				bodyCfg = getJSONBodyJSON(attrs, m.ctx)
+			}
+			cfg := m.resolveConfig(bodyCfg).(map[string]interface{})
+			cfg[provider.HCLCanonicalKey] = n.Canonical
 			resourcesRawConfig[n.ID] = cfg
-		} else {
-			// If it's not a hclsyntax.Body normally
-			// means it's from a JSON file, the only
-			// way to work with them is with rv.Config.JustAttributes()
-			// and manually deal with them. For what I've tested
-			// the Blocks (so egess an ingress for example) do not
-			// work and fail so we should find a workaround.
-			return nil, errcode.ErrGenerateFromJSON
-		}
 
-		for _, resources := range links {
-			nodeIDEdges[n.ID] = append(nodeIDEdges[n.ID], resources...)
+			// The links could be to variables, locals or outputs
+			// of modules so they are resolved to the resources
+			for _, resources := range links {
+				for _, r := range m.resolveAll(resources) {
+					nodeIDEdges[n.ID] = append(nodeIDEdges[n.ID], r.address())
+				}
+			}
 		}
 	}
 
 	for nid, resources := range nodeIDEdges {
-		for _, rkattr := range resources {
-			keys := strings.Split(rkattr, ".")
-
-			rk := rkattr
-			// If the values are like 'aws_security_group.front.id'
-			// and we do not need the 'id' as it's not on the key
-			// so we subtract it from the key
-			// But it's not always the case
-			if len(keys) == 3 {
-				rk = strings.Join(keys[:len(keys)-1], ".")
-			}
-
+		for _, rk := range resources {
 			tnid, ok := nodeCanID[rk]
 			if !ok {
 				continue
@@ -151,6 +172,12 @@ func FromHCL(fs afero.Fs, path string, opt Options) (*graph.Graph, error) {
 		}
 	}
 
+	if err := addGroups(g, resourcesRawConfig, nil, opt); err != nil {
+		return nil, err
+	}
+
+	addAttributes(g, resourcesRawConfig, opt)
+
 	// call the preprocess method for each
 	// TF provider in the file
 	if err := preprocess(g, resourcesRawConfig, opt); err != nil {
@@ -203,10 +230,20 @@ func getBodyLinks(b *hclsyntax.Body) map[string][]string {
 }
 
 // getBodyJSON gets all the variables in a JSON format
-// of the actual representation
-func getBodyJSON(b *hclsyntax.Body) map[string]interface{} {
+// of the actual representation. The values that can be
+// evaluated with the ctx, like the variables or the locals,
+// are set and the rest are the references to the resources
+func getBodyJSON(b *hclsyntax.Body, ctx *hcl.EvalContext) map[string]interface{} {
 	links := make(map[string]interface{})
 	for attrk, attrv := range b.Attributes {
+		if v, ok := evalExpr(attrv.Expr, ctx); ok {
+			// We continue to not add empty information to the config
+			// so it's clean and only has required information
+			if v != nil {
+				links[attrk] = v
+			}
+			continue
+		}
 		v, _ := attrv.Expr.Value(nil)
 		t := v.Type().FriendlyName()
 		switch t {
@@ -236,10 +273,19 @@ func getBodyJSON(b *hclsyntax.Body) map[string]interface{} {
 				aux = islice
 			}
 			links[attrk] = aux
+		case "object":
+			// The objects, like the 'tags', are only
+			// kept if they can be evaluated
+			if !v.IsWhollyKnown() {
+				continue
+			}
+			if m, ok := hcl2shim.ConfigValueFromHCL2(v).(map[string]interface{}); ok && len(m) != 0 {
+				links[attrk] = m
+			}
 		}
 	}
 	for _, block := range b.Blocks {
-		cfg := getBodyJSON(block.Body)
+		cfg := getBodyJSON(block.Body, ctx)
 		// We continue to not add empty information to the config
 		// so it's clean and only has required information
 		if len(cfg) == 0 {
@@ -254,21 +300,159 @@ func getBodyJSON(b *hclsyntax.Body) map[string]interface{} {
 	return links
 }
 
-// checkHCLProviders checks if we support any of the Providers from f, if not it'll set
-// the opt.Raw to true so it can be used with Raw instead of returning an empty Graph
-func checkHCLProviders(mod *configs.Module, opt Options) (Options, error) {
-	for rk := range mod.ManagedResources {
-		_, _, err := getProviderAndResource(rk, opt)
-		if err != nil {
-			if errors.Is(err, errcode.ErrProviderNotFound) {
+// evalExpr evaluates the e with the ctx and returns the value
+// if it's known, the empty maps are returned as nil
+func evalExpr(e hcl.Expression, ctx *hcl.EvalContext) (interface{}, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+
+	v, diags := e.Value(ctx)
+	if diags.HasErrors() || v.IsNull() || !v.IsWhollyKnown() {
+		return nil, false
+	}
+
+	cv := hcl2shim.ConfigValueFromHCL2(v)
+	if m, ok := cv.(map[string]interface{}); ok && len(m) == 0 {
+		return nil, true
+	}
+
+	return cv, true
+}
+
+// getJSONBodyLinks is the getBodyLinks of the
+// bodies of the JSON files ('*.tf.json')
+func getJSONBodyLinks(attrs hcl.Attributes) map[string][]string {
+	links := make(map[string][]string)
+	for attrk, attrv := range attrs {
+		for _, vr := range attrv.Expr.Variables() {
+			links[attrk] = append(links[attrk], string(hclwrite.TokensForTraversal(vr).Bytes()))
+		}
+	}
+
+	return links
+}
+
+// getJSONBodyJSON is the getBodyJSON of the bodies of the JSON files ('*.tf.json').
+// As there is no schema to know which keys are blocks, the objects with only
+// strings (like the 'tags') are kept as objects and the rest are read as
+// blocks (like the 'ingress'), which can also be a list of objects
+func getJSONBodyJSON(attrs hcl.Attributes, ctx *hcl.EvalContext) map[string]interface{} {
+	exprs := make(map[string]hcl.Expression, len(attrs))
+	for attrk, attrv := range attrs {
+		exprs[attrk] = attrv.Expr
+	}
+
+	return getJSONExprsJSON(exprs, ctx)
+}
+
+// getJSONExprsJSON returns the JSON format of the exprs of a body
+func getJSONExprsJSON(exprs map[string]hcl.Expression, ctx *hcl.EvalContext) map[string]interface{} {
+	links := make(map[string]interface{})
+	for k, e := range exprs {
+		v, _ := e.Value(nil)
+		switch {
+		case v.IsNull():
+		case v.Type().IsTupleType():
+			aux := make([]interface{}, 0)
+			ies, _ := hcl.ExprList(e)
+			for _, ie := range ies {
+				iv, _ := ie.Value(nil)
+				if iv.Type().IsObjectType() {
+					// A list of blocks
+					if cfg := getJSONExprsJSON(jsonExprMap(ie), ctx); len(cfg) != 0 {
+						aux = append(aux, cfg)
+					}
+				} else if !iv.IsNull() {
+					aux = append(aux, getJSONExprValue(ie, iv, ctx))
+				}
+			}
+			// We continue to not add empty information to the config
+			// so it's clean and only has required information
+			if len(aux) != 0 {
+				links[k] = aux
+			}
+		case v.Type().IsObjectType():
+			if isJSONStringObject(v) {
+				// The objects, like the 'tags', are only
+				// kept if they can be evaluated
+				if len(e.Variables()) != 0 {
+					if cv, ok := evalExpr(e, ctx); ok && cv != nil {
+						links[k] = cv
+					}
+					continue
+				}
+				if m, ok := hcl2shim.ConfigValueFromHCL2(v).(map[string]interface{}); ok && len(m) != 0 {
+					links[k] = m
+				}
 				continue
 			}
-			return opt, err
+			if cfg := getJSONExprsJSON(jsonExprMap(e), ctx); len(cfg) != 0 {
+				links[k] = []interface{}{cfg}
+			}
+		default:
+			links[k] = getJSONExprValue(e, v, ctx)
+		}
+	}
+
+	return links
+}
+
+// getJSONExprValue returns the value of the primitive e evaluated with
+// the ctx or if it can not be the first variable like '${aws_lb.front.id}'
+func getJSONExprValue(e hcl.Expression, v cty.Value, ctx *hcl.EvalContext) interface{} {
+	if cv, ok := evalExpr(e, ctx); ok {
+		return cv
+	}
+	for _, vr := range e.Variables() {
+		return fmt.Sprintf("${%s}", string(hclwrite.TokensForTraversal(vr).Bytes()))
+	}
+	return hcl2shim.ConfigValueFromHCL2(v)
+}
+
+// jsonExprMap returns the expressions of
+// each key of the object e
+func jsonExprMap(e hcl.Expression) map[string]hcl.Expression {
+	exprs := make(map[string]hcl.Expression)
+	kvs, _ := hcl.ExprMap(e)
+	for _, kv := range kvs {
+		k, _ := kv.Key.Value(nil)
+		if k.Type() != cty.String || k.IsNull() || k.AsString() == "//" {
+			continue
 		}
+		exprs[k.AsString()] = kv.Value
+	}
+	return exprs
+}
+
+// isJSONStringObject checks if all the
+// attributes of the object v are strings
+func isJSONStringObject(v cty.Value) bool {
+	for _, t := range v.Type().AttributeTypes() {
+		if t != cty.String {
+			return false
+		}
+	}
+	return true
+}
+
+// checkHCLProviders checks if we support any of the Providers from f, if not it'll set
+// the opt.Raw to true so it can be used with Raw instead of returning an empty Graph
+func checkHCLProviders(modules []*hclModule, opt Options) (Options, error) {
+	for _, m := range modules {
+		for rk := range m.mod.ManagedResources {
+			_, _, err := getProviderAndResource(rk, opt)
+			if err != nil {
+				if errors.Is(err, errcode.ErrProviderNotFound) {
+					continue
+				}
+				return opt, err
+			}
 
-		// If we find a resource that we support the Provider
-		// then we use it
-		return opt, nil
+			// If we find a resource that we support the Provider
+			// then we use it
+			return opt, nil
+		}
 	}
 
 	// If we reach here means the we do not support the providers
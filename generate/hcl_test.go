@@ -6,6 +6,7 @@ import (
 	"github.com/cycloidio/inframap/generate"
 	"github.com/cycloidio/inframap/graph"
 	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -67,6 +68,84 @@ func TestFromHCL_AWS(t *testing.T) {
 			},
 		}
 
+		assertEqualGraph(t, eg, g, nil)
+	})
+	t.Run("SuccessWithAttributes", func(t *testing.T) {
+		fs := afero.NewOsFs()
+
+		g, err := generate.FromHCL(fs, "./testdata/aws_hcl_sg.tf", generate.Options{Clean: true, Connections: true, ExternalNodes: true, Attributes: []string{"tags.Name", "name", "tags.missing"}})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+
+		n, err := g.GetNodeByCanonical("aws_lb.front")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"tags.Name": "name", "name": "some name"}, n.Attributes)
+
+		n, err = g.GetNodeByCanonical("aws_elasticache_cluster.redis")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"tags.Name": "name"}, n.Attributes)
+	})
+	t.Run("SuccessJSON", func(t *testing.T) {
+		fs := afero.NewOsFs()
+
+		g, err := generate.FromHCL(fs, "./testdata/aws_hcl_sg.tf.json", generate.Options{Clean: true, Connections: true, ExternalNodes: true, Attributes: []string{"tags.Name"}})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				{
+					Canonical: "im_out.tcp/443->443",
+				},
+				{
+					Canonical: "im_out.tcp/80->80",
+				},
+				{
+					Canonical:  "aws_lb.front",
+					Attributes: map[string]interface{}{"tags.Name": "name"},
+				},
+				{
+					Canonical:  "aws_launch_template.front",
+					Attributes: map[string]interface{}{"tags.Name": "name"},
+				},
+				{
+					Canonical:  "aws_db_instance.application",
+					Attributes: map[string]interface{}{"tags.Name": "name"},
+				},
+				{
+					Canonical:  "aws_elasticache_cluster.redis",
+					Attributes: map[string]interface{}{"tags.Name": "name"},
+				},
+			},
+			Edges: []*graph.Edge{
+				{
+					Source:     "im_out.tcp/443->443",
+					Target:     "aws_lb.front",
+					Canonicals: []string(nil),
+				},
+				{
+					Source:     "im_out.tcp/80->80",
+					Target:     "aws_lb.front",
+					Canonicals: []string(nil),
+				},
+				{
+					Source:     "aws_lb.front",
+					Target:     "aws_launch_template.front",
+					Canonicals: []string{"aws_security_group.lb-front", "aws_security_group.front"},
+				},
+				{
+					Source:     "aws_launch_template.front",
+					Target:     "aws_db_instance.application",
+					Canonicals: []string{"aws_security_group.front", "aws_security_group.rds"},
+				},
+				{
+					Source:     "aws_launch_template.front",
+					Target:     "aws_elasticache_cluster.redis",
+					Canonicals: []string{"aws_security_group.redis", "aws_security_group.front"},
+				},
+			},
+		}
+
 		assertEqualGraph(t, eg, g, nil)
 	})
 }
@@ -119,9 +198,11 @@ func TestFromHCL_Google(t *testing.T) {
 			Nodes: []*graph.Node{
 				{
 					Canonical: "google_compute_instance.inframap-tmp-two",
+					Group:     "google_compute_network.vpc_network/region.us-east1",
 				},
 				{
 					Canonical: "google_compute_instance.inframap-tmp",
+					Group:     "google_compute_network.vpc_network/region.us-east1",
 				},
 			},
 			Edges: []*graph.Edge{
@@ -199,4 +280,215 @@ func TestFromHCL_Module(t *testing.T) {
 
 		assertEqualGraph(t, eg, g, nil)
 	})
+	t.Run("SuccessModuleCalls", func(t *testing.T) {
+		fs := afero.NewOsFs()
+
+		g, err := generate.FromHCL(fs, "./testdata/hcl-modules/", generate.Options{Clean: true, Connections: true, ExternalNodes: true})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				{
+					Canonical: "im_out.tcp/443->443",
+				},
+				{
+					Canonical: "aws_lb.front",
+				},
+				{
+					Canonical: "module.app.aws_instance.web",
+				},
+				{
+					Canonical: "module.db.aws_db_instance.main",
+				},
+			},
+			Edges: []*graph.Edge{
+				{
+					Source:     "im_out.tcp/443->443",
+					Target:     "aws_lb.front",
+					Canonicals: []string(nil),
+				},
+				{
+					Source:     "aws_lb.front",
+					Target:     "module.app.aws_instance.web",
+					Canonicals: []string{"aws_security_group.lb", "module.app.aws_security_group.app"},
+					Ports:      []graph.Port{{Protocol: "tcp", FromPort: 80, ToPort: 80}},
+				},
+				{
+					Source:     "module.app.aws_instance.web",
+					Target:     "module.db.aws_db_instance.main",
+					Canonicals: []string{"module.app.aws_security_group.app", "module.db.aws_security_group.db"},
+					Ports:      []graph.Port{{Protocol: "tcp", FromPort: 3306, ToPort: 3306}},
+				},
+			},
+		}
+
+		assertEqualGraph(t, eg, g, nil)
+	})
+}
+
+func TestFromHCL_Variables(t *testing.T) {
+	t.Run("SuccessAutoTFVars", func(t *testing.T) {
+		fs := afero.NewOsFs()
+
+		g, err := generate.FromHCL(fs, "./testdata/hcl-vars/", generate.Options{Clean: true, Connections: true, ExternalNodes: true})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				{
+					Canonical: "im_out.tcp/80->80",
+				},
+				{
+					Canonical: "im_out.tcp/443->443",
+				},
+				{
+					Canonical: "aws_lb.front",
+				},
+				{
+					Canonical: "aws_instance.app",
+				},
+			},
+			Edges: []*graph.Edge{
+				{
+					Source:     "im_out.tcp/80->80",
+					Target:     "aws_lb.front",
+					Canonicals: []string(nil),
+				},
+				{
+					Source:     "im_out.tcp/443->443",
+					Target:     "aws_lb.front",
+					Canonicals: []string(nil),
+				},
+				{
+					Source:     "aws_lb.front",
+					Target:     "aws_instance.app",
+					Canonicals: []string{"aws_security_group.lb", "aws_security_group.app"},
+					Ports:      []graph.Port{{Protocol: "tcp", FromPort: 80, ToPort: 80}},
+				},
+			},
+		}
+
+		assertEqualGraph(t, eg, g, nil)
+	})
+	t.Run("SuccessVars", func(t *testing.T) {
+		fs := afero.NewOsFs()
+
+		g, err := generate.FromHCL(fs, "./testdata/hcl-vars/", generate.Options{Clean: true, Connections: true, ExternalNodes: true, Vars: map[string]string{"port": "8080", "missing": "value"}})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				{
+					Canonical: "im_out.tcp/8080->8080",
+				},
+				{
+					Canonical: "im_out.tcp/443->443",
+				},
+				{
+					Canonical: "aws_lb.front",
+				},
+				{
+					Canonical: "aws_instance.app",
+				},
+			},
+			Edges: []*graph.Edge{
+				{
+					Source:     "im_out.tcp/8080->8080",
+					Target:     "aws_lb.front",
+					Canonicals: []string(nil),
+				},
+				{
+					Source:     "im_out.tcp/443->443",
+					Target:     "aws_lb.front",
+					Canonicals: []string(nil),
+				},
+				{
+					Source:     "aws_lb.front",
+					Target:     "aws_instance.app",
+					Canonicals: []string{"aws_security_group.lb", "aws_security_group.app"},
+					Ports:      []graph.Port{{Protocol: "tcp", FromPort: 8080, ToPort: 8080}},
+				},
+			},
+		}
+
+		assertEqualGraph(t, eg, g, nil)
+	})
+	t.Run("SuccessVarFiles", func(t *testing.T) {
+		fs := afero.NewOsFs()
+
+		g, err := generate.FromHCL(fs, "./testdata/hcl-vars/", generate.Options{Clean: true, Connections: true, ExternalNodes: true, VarFiles: []string{"./testdata/hcl-vars/private.tfvars"}})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				{
+					Canonical: "aws_lb.front",
+				},
+				{
+					Canonical: "aws_instance.app",
+				},
+			},
+			Edges: []*graph.Edge{
+				{
+					Source:     "aws_lb.front",
+					Target:     "aws_instance.app",
+					Canonicals: []string{"aws_security_group.lb", "aws_security_group.app"},
+				},
+			},
+		}
+
+		assertEqualGraph(t, eg, g, nil)
+	})
+	t.Run("SuccessFileOnFs", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "port", []byte("8080\n"), 0644))
+		require.NoError(t, afero.WriteFile(fs, "module.tf", []byte(`
+resource "aws_security_group" "lb" {
+  ingress {
+    from_port   = tonumber(trimspace(file("port")))
+    to_port     = tonumber(trimspace(file("port")))
+    protocol    = "tcp"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+}
+
+resource "aws_lb" "front" {
+  security_groups = [aws_security_group.lb.id]
+}
+`), 0644))
+
+		g, err := generate.FromHCL(fs, "module.tf", generate.Options{Clean: true, Connections: true, ExternalNodes: true})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				{
+					Canonical: "im_out.tcp/8080->8080",
+				},
+				{
+					Canonical: "aws_lb.front",
+				},
+			},
+			Edges: []*graph.Edge{
+				{
+					Source:     "im_out.tcp/8080->8080",
+					Target:     "aws_lb.front",
+					Canonicals: []string(nil),
+				},
+			},
+		}
+
+		assertEqualGraph(t, eg, g, nil)
+	})
+	t.Run("ErrorVarFile", func(t *testing.T) {
+		fs := afero.NewOsFs()
+
+		_, err := generate.FromHCL(fs, "./testdata/hcl-vars/", generate.Options{VarFiles: []string{"./testdata/hcl-vars/missing.tfvars"}})
+		assert.Error(t, err)
+	})
 }
@@ -71,6 +71,11 @@ func assertEqualGraph(t *testing.T, expected, actual *graph.Graph, actualCfg map
 			sort.Strings(e.Canonicals)
 			sort.Strings(ee.Canonicals)
 			assert.Equal(t, ee.Canonicals, e.Canonicals, fmt.Sprintf("Source: %s Target: %s", ee.Source, ee.Target))
+
+			// The Ports are only checked if they are expected
+			if ee.Ports != nil {
+				assert.ElementsMatch(t, ee.Ports, e.Ports, fmt.Sprintf("Source: %s Target: %s", ee.Source, ee.Target))
+			}
 		}
 	}
 
@@ -0,0 +1,299 @@
+package generate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform/configs"
+	"github.com/spf13/afero"
+)
+
+// hclModule is a module of the HCL configuration
+// with all the modules it calls
+type hclModule struct {
+	// path it's the module path like 'module.app'
+	path string
+	dir  string
+	mod  *configs.Module
+
+	// parent is the module that calls this
+	// one with the call
+	parent *hclModule
+	call   *configs.ModuleCall
+
+	children map[string]*hclModule
+
+	// ctx is the context used to evaluate
+	// the expressions of the module
+	ctx *hcl.EvalContext
+}
+
+// reInterpolation matches the '${aws_lb.front.id}'
+// set on the configuration by getBodyJSON
+var reInterpolation = regexp.MustCompile(`^\$\{(.+)\}$`)
+
+// modulesManifest is the '.terraform/modules/modules.json'
+// written by Terraform when the modules are installed
+type modulesManifest struct {
+	Modules []struct {
+		// Key it's the path of the calls like 'app.db'
+		Key string `json:"Key"`
+		Dir string `json:"Dir"`
+	} `json:"Modules"`
+}
+
+// readModulesManifest reads the modules installed on the dir and
+// returns the Key of each module with the directory it's on
+func readModulesManifest(fs afero.Fs, dir string) (map[string]string, error) {
+	manifest := make(map[string]string)
+
+	b, err := afero.ReadFile(fs, filepath.Join(dir, ".terraform", "modules", "modules.json"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return manifest, nil
+		}
+		return nil, err
+	}
+
+	var mm modulesManifest
+	if err := json.Unmarshal(b, &mm); err != nil {
+		return nil, fmt.Errorf("invalid modules manifest: %w", err)
+	}
+
+	for _, m := range mm.Modules {
+		manifest[m.Key] = filepath.Join(dir, m.Dir)
+	}
+
+	return manifest, nil
+}
+
+// loadChildren loads all the modules called by m, and the ones
+// called by them, from the installed modules of the manifest or if
+// not installed from the local sources. The ones that can not be
+// found are ignored. The key is the path of the calls
+// to m on the manifest like 'app.db'
+func (m *hclModule) loadChildren(parser *configs.Parser, key string, manifest map[string]string) error {
+	m.children = make(map[string]*hclModule)
+	for name, call := range m.mod.ModuleCalls {
+		ckey := name
+		if key != "" {
+			ckey = fmt.Sprintf("%s.%s", key, name)
+		}
+
+		dir, ok := manifest[ckey]
+		if !ok {
+			if !isLocalSource(call.SourceAddr) {
+				continue
+			}
+			dir = filepath.Join(m.dir, call.SourceAddr)
+		}
+
+		// A module calling itself or any of the ones
+		// calling it would never end
+		if m.isAncestor(dir) || !parser.IsConfigDir(dir) {
+			continue
+		}
+
+		mod, diags := parser.LoadConfigDir(dir)
+		if diags.HasErrors() {
+			return errors.New(diags.Error())
+		}
+
+		c := &hclModule{
+			path:   prefixWithModule(m.path, fmt.Sprintf("module.%s", name)),
+			dir:    dir,
+			mod:    mod,
+			parent: m,
+			call:   call,
+		}
+		if err := c.loadChildren(parser, ckey, manifest); err != nil {
+			return err
+		}
+
+		m.children[name] = c
+	}
+
+	return nil
+}
+
+// isAncestor checks if the dir is the one of m
+// or of any of the modules calling it
+func (m *hclModule) isAncestor(dir string) bool {
+	for a := m; a != nil; a = a.parent {
+		if filepath.Clean(a.dir) == filepath.Clean(dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// modules returns the m and all the
+// modules it calls sorted by path
+func (m *hclModule) modules() []*hclModule {
+	mods := []*hclModule{m}
+
+	names := make([]string, 0, len(m.children))
+	for n := range m.children {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	for _, n := range names {
+		mods = append(mods, m.children[n].modules()...)
+	}
+
+	return mods
+}
+
+// resolveAll resolves all the refs, like 'aws_lb.front.id',
+// to the resources that they reference following the variables,
+// the locals and the outputs of the modules
+func (m *hclModule) resolveAll(refs []string) []reference {
+	res := make([]reference, 0)
+	seen := make(map[string]struct{})
+	for _, ref := range refs {
+		res = append(res, m.resolve(ref, seen)...)
+	}
+	return res
+}
+
+// resolve resolves the ref, the seen are the refs
+// already resolved to not follow them again
+func (m *hclModule) resolve(ref string, seen map[string]struct{}) []reference {
+	key := fmt.Sprintf("%s/%s", m.path, ref)
+	if _, ok := seen[key]; ok {
+		return nil
+	}
+	seen[key] = struct{}{}
+
+	parts := strings.Split(reIndex.ReplaceAllString(ref, ""), ".")
+	if len(parts) < 2 {
+		return nil
+	}
+
+	switch parts[0] {
+	case "var":
+		// The variables are the arguments
+		// of the module call on the parent
+		if m.call == nil {
+			return nil
+		}
+		// The ones from JSON files ('*.tf.json') are
+		// not a hclsyntax.Body so they only have attributes
+		var expr hcl.Expression
+		if body, ok := m.call.Config.(*hclsyntax.Body); ok {
+			attr, ok := body.Attributes[parts[1]]
+			if !ok {
+				return nil
+			}
+			expr = attr.Expr
+		} else {
+			attrs, _ := m.call.Config.JustAttributes()
+			attr, ok := attrs[parts[1]]
+			if !ok {
+				return nil
+			}
+			expr = attr.Expr
+		}
+		return m.parent.resolveExpr(expr, seen)
+	case "local":
+		l, ok := m.mod.Locals[parts[1]]
+		if !ok {
+			return nil
+		}
+		return m.resolveExpr(l.Expr, seen)
+	case "module":
+		if len(parts) < 3 {
+			return nil
+		}
+		c, ok := m.children[parts[1]]
+		if !ok {
+			return nil
+		}
+		o, ok := c.mod.Outputs[parts[2]]
+		if !ok {
+			return nil
+		}
+		return c.resolveExpr(o.Expr, seen)
+	}
+
+	if r, ok := parseReference(m.path, parts); ok {
+		return []reference{r}
+	}
+
+	return nil
+}
+
+// resolveExpr resolves all the variables of the e
+func (m *hclModule) resolveExpr(e hcl.Expression, seen map[string]struct{}) []reference {
+	res := make([]reference, 0)
+	for _, vr := range e.Variables() {
+		res = append(res, m.resolve(string(hclwrite.TokensForTraversal(vr).Bytes()), seen)...)
+	}
+	return res
+}
+
+// resolveConfig replaces the interpolations of the v, made by getBodyJSON,
+// with the resources they reference like '${module.app.aws_lb.front.id}'
+// so they can be matched with the Canonical of the Nodes
+func (m *hclModule) resolveConfig(v interface{}) interface{} {
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		for k, e := range vv {
+			vv[k] = m.resolveConfig(e)
+		}
+	case []interface{}:
+		res := make([]interface{}, 0, len(vv))
+		for _, e := range vv {
+			if refs := m.resolveInterpolation(e); len(refs) != 0 {
+				res = append(res, refs...)
+			} else {
+				res = append(res, m.resolveConfig(e))
+			}
+		}
+		return res
+	default:
+		if refs := m.resolveInterpolation(v); len(refs) != 0 {
+			return refs[0]
+		}
+	}
+	return v
+}
+
+// resolveInterpolation returns the resources referenced
+// by the v if it's an interpolation
+func (m *hclModule) resolveInterpolation(v interface{}) []interface{} {
+	s, ok := v.(string)
+	if !ok {
+		return nil
+	}
+
+	res := reInterpolation.FindStringSubmatch(s)
+	if len(res) == 0 {
+		return nil
+	}
+
+	refs := make([]interface{}, 0)
+	for _, r := range m.resolveAll([]string{res[1]}) {
+		if r.attr != "" {
+			refs = append(refs, r.variable())
+		}
+	}
+
+	return refs
+}
+
+// isLocalSource checks if the source of a
+// module call is a local path
+func isLocalSource(src string) bool {
+	return strings.HasPrefix(src, "./") || strings.HasPrefix(src, "../")
+}
@@ -19,4 +19,52 @@ type Options struct {
 	// Nodes detected to make the graph better,
 	// like the 'im_out'
 	ExternalNodes bool
+
+	// Attributes is the list of paths of the attributes
+	// (ex: 'tags.Name') of each resource that will be kept
+	// on the graph.Node.Attributes
+	Attributes []string
+
+	// Instances is how the instances of the resources
+	// with 'count' or 'for_each' are represented, by
+	// default only the first one is
+	Instances InstancesMode
+
+	// Vars are the values of the variables of the HCL,
+	// like the '-var' of Terraform, the values are
+	// parsed as HCL if the variable is not a string
+	Vars map[string]string
+
+	// VarFiles are the paths of the '.tfvars' files used
+	// for the variables of the HCL, like the '-var-file' of
+	// Terraform. The 'terraform.tfvars' and '*.auto.tfvars'
+	// are always used if present and the Vars have
+	// priority over all of them
+	VarFiles []string
+}
+
+// InstancesMode is how the instances of the
+// resources with 'count' or 'for_each' are
+// represented on the graph
+type InstancesMode string
+
+// List of all the InstancesMode
+const (
+	// InstancesFirst represents the resource only
+	// by its first instance
+	InstancesFirst InstancesMode = "first"
+
+	// InstancesExpand represents each instance with
+	// one Node, like 'aws_instance.web[3]'
+	InstancesExpand InstancesMode = "expand"
+
+	// InstancesCollapse represents the resource with
+	// one Node with the number of instances it has
+	// on the graph.Node.Instances
+	InstancesCollapse InstancesMode = "collapse"
+)
+
+// InstancesModeStrings returns all the InstancesMode
+func InstancesModeStrings() []string {
+	return []string{string(InstancesFirst), string(InstancesExpand), string(InstancesCollapse)}
 }
@@ -0,0 +1,626 @@
+package generate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cycloidio/inframap/errcode"
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/provider"
+	uuid "github.com/satori/go.uuid"
+)
+
+// plan is the JSON representation of a plan, or a state,
+// returned by 'terraform show -json'
+type plan struct {
+	FormatVersion   string               `json:"format_version"`
+	PlannedValues   *planValues          `json:"planned_values"`
+	Values          *planValues          `json:"values"`
+	PriorState      *planState           `json:"prior_state"`
+	ResourceChanges []planResourceChange `json:"resource_changes"`
+	Configuration   *planConfig          `json:"configuration"`
+}
+
+type planState struct {
+	Values *planValues `json:"values"`
+}
+
+type planValues struct {
+	RootModule planModule `json:"root_module"`
+}
+
+type planModule struct {
+	Address      string         `json:"address"`
+	Resources    []planResource `json:"resources"`
+	ChildModules []planModule   `json:"child_modules"`
+}
+
+type planResource struct {
+	Address   string                 `json:"address"`
+	Mode      string                 `json:"mode"`
+	Type      string                 `json:"type"`
+	Name      string                 `json:"name"`
+	Values    map[string]interface{} `json:"values"`
+	DependsOn []string               `json:"depends_on"`
+}
+
+type planResourceChange struct {
+	Address       string     `json:"address"`
+	ModuleAddress string     `json:"module_address"`
+	Mode          string     `json:"mode"`
+	Type          string     `json:"type"`
+	Name          string     `json:"name"`
+	Change        planChange `json:"change"`
+}
+
+type planChange struct {
+	Actions      []string               `json:"actions"`
+	Before       map[string]interface{} `json:"before"`
+	AfterUnknown map[string]interface{} `json:"after_unknown"`
+}
+
+type planConfig struct {
+	RootModule planConfigModule `json:"root_module"`
+}
+
+type planConfigModule struct {
+	Outputs     map[string]planConfigOutput     `json:"outputs"`
+	Resources   []planConfigResource            `json:"resources"`
+	ModuleCalls map[string]planConfigModuleCall `json:"module_calls"`
+}
+
+type planConfigOutput struct {
+	Expression map[string]interface{} `json:"expression"`
+}
+
+type planConfigResource struct {
+	Mode        string                 `json:"mode"`
+	Type        string                 `json:"type"`
+	Name        string                 `json:"name"`
+	Expressions map[string]interface{} `json:"expressions"`
+	DependsOn   []string               `json:"depends_on"`
+}
+
+type planConfigModuleCall struct {
+	Expressions map[string]interface{} `json:"expressions"`
+	Module      planConfigModule       `json:"module"`
+}
+
+// planNode is a resource of the plan
+// that will be a graph.Node
+type planNode struct {
+	canonical string
+	rtype     string
+	name      string
+	address   string
+	values    map[string]interface{}
+
+	// instances is the number of instances
+	// of the resource the node represents
+	instances int
+}
+
+// IsPlan checks if the b is the JSON output of 'terraform show -json',
+// of a plan or of a state, which can be used with FromPlan
+func IsPlan(b []byte) bool {
+	var p struct {
+		FormatVersion string          `json:"format_version"`
+		PlannedValues json.RawMessage `json:"planned_values"`
+		Values        json.RawMessage `json:"values"`
+	}
+	if err := json.Unmarshal(b, &p); err != nil {
+		return false
+	}
+
+	return p.FormatVersion != "" && (p.PlannedValues != nil || p.Values != nil)
+}
+
+// FromPlan generates a graph.Graph from the JSON output of
+// 'terraform show -json', of a plan or of a state, applying the opt.
+// The Nodes are the planned values and the Edges are the references
+// between them on the configuration. Each Node has the Action
+// that the plan will do to it
+func FromPlan(b json.RawMessage, opt Options) (*graph.Graph, error) {
+	var p plan
+	if err := json.Unmarshal(b, &p); err != nil {
+		return nil, fmt.Errorf("%s: %w", err, errcode.ErrInvalidTFPlanFile)
+	}
+
+	values := p.PlannedValues
+	if values == nil {
+		values = p.Values
+	}
+	if p.FormatVersion == "" || values == nil {
+		return nil, errcode.ErrInvalidTFPlanFile
+	}
+
+	// changes holds as key the address of the
+	// resource and as value the change it has
+	changes := make(map[string]planChange)
+	for _, rc := range p.ResourceChanges {
+		changes[rc.Address] = rc.Change
+	}
+
+	nodes := make([]planNode, 0)
+	// The instances of the count/for_each are represented
+	// by the first one unless they are expanded, seen holds
+	// the index on the nodes of each canonical
+	seen := make(map[string]int)
+	walkPlanModule(values.RootModule, func(m planModule, r planResource) {
+		if r.Mode != "managed" {
+			return
+		}
+		can := planCanonical(m.Address, r.Address, r.Type, r.Name, opt)
+		if i, ok := seen[can]; ok {
+			nodes[i].instances++
+			return
+		}
+		seen[can] = len(nodes)
+		nodes = append(nodes, planNode{canonical: can, rtype: r.Type, name: r.Name, address: r.Address, values: r.Values, instances: 1})
+	})
+
+	// The deleted resources are not on the planned
+	// values so we take them from the changes
+	deleted := make(map[string]struct{})
+	for _, rc := range p.ResourceChanges {
+		if rc.Mode != "managed" || planAction(rc.Change.Actions) != graph.ActionDelete {
+			continue
+		}
+		can := planCanonical(rc.ModuleAddress, rc.Address, rc.Type, rc.Name, opt)
+		if i, ok := seen[can]; ok {
+			if _, ok := deleted[can]; ok {
+				nodes[i].instances++
+			}
+			continue
+		}
+		seen[can] = len(nodes)
+		deleted[can] = struct{}{}
+		nodes = append(nodes, planNode{canonical: can, rtype: rc.Type, name: rc.Name, address: rc.Address, values: rc.Change.Before, instances: 1})
+	}
+
+	// deps holds as key the configuration address (without the keys
+	// of the instances) and as value all the configuration addresses
+	// it depends on
+	deps := make(map[string][]string)
+
+	// scopes holds as key the configuration address and as
+	// value the configuration of it and the module it's in
+	scopes := make(map[string]planConfigResourceScope)
+	if p.Configuration != nil {
+		walkPlanConfigModule(&planScope{module: &p.Configuration.RootModule}, func(s *planScope, r planConfigResource) {
+			if r.Mode != "managed" {
+				return
+			}
+			addr := prefixWithModule(s.path, fmt.Sprintf("%s.%s", r.Type, r.Name))
+			scopes[addr] = planConfigResourceScope{scope: s, resource: r}
+
+			refs := append(expressionReferences(r.Expressions), r.DependsOn...)
+			for _, ref := range s.resolveAll(refs) {
+				deps[addr] = append(deps[addr], ref.address())
+			}
+		})
+	}
+
+	// The 'depends_on' of the state are
+	// also used if they are present
+	for _, st := range []*planState{p.PriorState, {Values: p.Values}} {
+		if st == nil || st.Values == nil {
+			continue
+		}
+		walkPlanModule(st.Values.RootModule, func(m planModule, r planResource) {
+			addr := prefixWithModule(reIndex.ReplaceAllString(m.Address, ""), fmt.Sprintf("%s.%s", r.Type, r.Name))
+			for _, d := range r.DependsOn {
+				deps[addr] = append(deps[addr], reIndex.ReplaceAllString(d, ""))
+			}
+		})
+	}
+
+	if !opt.Raw {
+		opt = checkPlanProviders(nodes, opt)
+	}
+
+	shapes := planShapes(nodes, p.ResourceChanges)
+
+	g := graph.New()
+
+	// cfg holds the actual configuration of each element
+	// it's represented as: ID -> Attrs
+	cfg := make(map[string]map[string]interface{})
+
+	// nodeAddrIDs holds as key the configuration address and
+	// as value the UUIDs (graph.Node.ID) of the Nodes of it
+	nodeAddrIDs := make(map[string][]string)
+
+	for _, pn := range nodes {
+		pv, rs, err := getProviderAndResource(fmt.Sprintf("%s.%s", pn.rtype, pn.name), opt)
+		if err != nil {
+			if errors.Is(err, errcode.ErrProviderNotFound) {
+				continue
+			}
+			return nil, err
+		}
+
+		// If it's not a Node or Edge we ignore it
+		if !pv.IsNode(rs) && !pv.IsEdge(rs) {
+			continue
+		}
+
+		res, err := pv.Resource(rs)
+		if err != nil {
+			return nil, err
+		}
+
+		aux := make(map[string]interface{}, len(pn.values))
+		for k, v := range pn.values {
+			aux[k] = v
+		}
+
+		// The values not known until apply are not on the
+		// planned values so they are filled with the references
+		// of the configuration like '${aws_security_group.front.id}'
+		addr := reIndex.ReplaceAllString(pn.canonical, "")
+		if sc, ok := scopes[addr]; ok {
+			for k, v := range sc.scope.expressionsConfig(sc.resource.Expressions, shapes[pn.rtype]) {
+				if _, ok := aux[k]; !ok {
+					aux[k] = v
+				}
+			}
+		}
+		aux[provider.HCLCanonicalKey] = pn.canonical
+
+		tfid, _ := pn.values["id"].(string)
+		n := &graph.Node{
+			ID:        uuid.NewV4().String(),
+			Canonical: pn.canonical,
+			TFID:      tfid,
+			Resource:  *res,
+			Action:    planAction(changes[pn.address].Actions),
+		}
+		if opt.Instances == InstancesCollapse {
+			n.Instances = pn.instances
+		}
+
+		err = g.AddNode(n)
+		if err != nil {
+			return nil, err
+		}
+
+		nodeAddrIDs[addr] = append(nodeAddrIDs[addr], n.ID)
+		cfg[n.ID] = aux
+	}
+
+	for _, n := range g.Nodes {
+		for _, d := range deps[reIndex.ReplaceAllString(n.Canonical, "")] {
+			for _, tid := range nodeAddrIDs[d] {
+				if tid == n.ID {
+					continue
+				}
+				err := g.AddEdge(&graph.Edge{
+					ID:     uuid.NewV4().String(),
+					Source: n.ID,
+					Target: tid,
+				})
+				if err != nil {
+					// If the edge already exists we can ignore it
+					if errors.Is(err, errcode.ErrGraphAlreadyExistsEdge) {
+						continue
+					}
+					return nil, err
+				}
+			}
+		}
+	}
+
+	if err := addGroups(g, cfg, nil, opt); err != nil {
+		return nil, err
+	}
+
+	addAttributes(g, cfg, opt)
+
+	// call the preprocess method for each
+	// TF provider in the file
+	if err := preprocess(g, cfg, opt); err != nil {
+		return nil, err
+	}
+
+	if opt.Clean {
+		g.Clean()
+	}
+
+	err := fixEdges(g, cfg, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.Connections {
+		err = mutate(g, opt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if opt.Clean {
+		err = cleanHangingEdges(g, opt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return g, nil
+}
+
+// planCanonical returns the canonical of the resource, it's the
+// address with the key of the instance if they are expanded
+func planCanonical(module, address, rtype, name string, opt Options) string {
+	if opt.Instances == InstancesExpand {
+		return address
+	}
+	// The instances of the modules with 'count' or 'for_each'
+	// ('module.app[0]') are also the same Node
+	return prefixWithModule(reIndex.ReplaceAllString(module, ""), fmt.Sprintf("%s.%s", rtype, name))
+}
+
+// planAction returns the graph.Node Action of the
+// actions of a change of the plan
+func planAction(actions []string) string {
+	// The replace is represented as a
+	// 'delete' and 'create' in any order
+	if len(actions) == 2 {
+		return graph.ActionReplace
+	}
+
+	if len(actions) == 1 {
+		switch actions[0] {
+		case "create":
+			return graph.ActionCreate
+		case "update":
+			return graph.ActionUpdate
+		case "delete":
+			return graph.ActionDelete
+		}
+	}
+
+	return ""
+}
+
+// planShapes returns as key the type of the resources and as value an example of
+// the values of each attribute, from all the resources of the type on the plan, the
+// planned values, the 'before' and the 'after_unknown' of the changes. It's used to
+// know the type of the attributes, as the lists, that are unknown on a resource
+func planShapes(nodes []planNode, rcs []planResourceChange) map[string]map[string]interface{} {
+	shapes := make(map[string]map[string]interface{})
+	add := func(rtype string, values map[string]interface{}) {
+		if _, ok := shapes[rtype]; !ok {
+			shapes[rtype] = make(map[string]interface{})
+		}
+		for k, v := range values {
+			// The values of the 'after_unknown' are
+			// 'true' if the whole value is unknown
+			if v == nil || v == true {
+				continue
+			}
+			// The lists with elements are preferred
+			// as they are also the shape of the blocks
+			if l, ok := shapes[rtype][k].([]interface{}); ok && len(l) != 0 {
+				continue
+			}
+			shapes[rtype][k] = v
+		}
+	}
+
+	for _, pn := range nodes {
+		add(pn.rtype, pn.values)
+	}
+	for _, rc := range rcs {
+		add(rc.Type, rc.Change.Before)
+		add(rc.Type, rc.Change.AfterUnknown)
+	}
+
+	return shapes
+}
+
+// checkPlanProviders checks if we support any of the Providers from nodes, if not it'll set
+// the opt.Raw to true so it can be used with Raw instead of returning an empty Graph
+func checkPlanProviders(nodes []planNode, opt Options) Options {
+	for _, pn := range nodes {
+		_, _, err := getProviderAndResource(fmt.Sprintf("%s.%s", pn.rtype, pn.name), opt)
+		if err == nil {
+			return opt
+		}
+	}
+
+	opt.Raw = true
+
+	return opt
+}
+
+// walkPlanModule calls fn with each resource of the m
+// and of all the child modules
+func walkPlanModule(m planModule, fn func(m planModule, r planResource)) {
+	for _, r := range m.Resources {
+		fn(m, r)
+	}
+	for _, cm := range m.ChildModules {
+		walkPlanModule(cm, fn)
+	}
+}
+
+// walkPlanConfigModule calls fn with each resource of
+// the module of s and of all the modules it calls
+func walkPlanConfigModule(s *planScope, fn func(s *planScope, r planConfigResource)) {
+	for _, r := range s.module.Resources {
+		fn(s, r)
+	}
+	for name := range s.module.ModuleCalls {
+		walkPlanConfigModule(s.child(name), fn)
+	}
+}
+
+// expressionReferences returns all the references
+// used on the expressions e, which could be nested
+// on blocks
+func expressionReferences(e interface{}) []string {
+	refs := make([]string, 0)
+	switch ev := e.(type) {
+	case map[string]interface{}:
+		if rs, ok := ev["references"].([]interface{}); ok {
+			for _, r := range rs {
+				if sr, ok := r.(string); ok {
+					refs = append(refs, sr)
+				}
+			}
+			return refs
+		}
+		for _, v := range ev {
+			refs = append(refs, expressionReferences(v)...)
+		}
+	case []interface{}:
+		for _, v := range ev {
+			refs = append(refs, expressionReferences(v)...)
+		}
+	}
+	return refs
+}
+
+// planConfigResourceScope is a resource of the
+// configuration and the module in which it is
+type planConfigResourceScope struct {
+	scope    *planScope
+	resource planConfigResource
+}
+
+// planScope is a module of the configuration,
+// used to resolve the references of it
+type planScope struct {
+	// path it's the module path like 'module.app'
+	path   string
+	module *planConfigModule
+
+	// parent is the scope that calls this
+	// one with the module call
+	parent *planScope
+	call   string
+}
+
+// child returns the scope of the module call name
+func (s *planScope) child(name string) *planScope {
+	mc := s.module.ModuleCalls[name]
+	return &planScope{
+		path:   prefixWithModule(s.path, fmt.Sprintf("module.%s", name)),
+		module: &mc.Module,
+		parent: s,
+		call:   name,
+	}
+}
+
+// resolveAll resolves all the refs to the resources
+// that they reference, following the variables and
+// the outputs of the modules
+func (s *planScope) resolveAll(refs []string) []reference {
+	res := make([]reference, 0)
+	for _, ref := range refs {
+		res = append(res, s.resolve(ref)...)
+	}
+	return res
+}
+
+func (s *planScope) resolve(ref string) []reference {
+	parts := strings.Split(reIndex.ReplaceAllString(ref, ""), ".")
+	if len(parts) < 2 {
+		return nil
+	}
+
+	switch parts[0] {
+	case "var":
+		// The variables are the expressions
+		// of the module call on the parent
+		if s.parent == nil {
+			return nil
+		}
+		mc := s.parent.module.ModuleCalls[s.call]
+		return s.parent.resolveAll(expressionReferences(mc.Expressions[parts[1]]))
+	case "module":
+		// The 'module.NAME' is always also referenced
+		// with the output 'module.NAME.OUTPUT' so
+		// we only use that one
+		if len(parts) < 3 {
+			return nil
+		}
+		if _, ok := s.module.ModuleCalls[parts[1]]; !ok {
+			return nil
+		}
+		cs := s.child(parts[1])
+		return cs.resolveAll(expressionReferences(cs.module.Outputs[parts[2]].Expression))
+	}
+
+	if r, ok := parseReference(s.path, parts); ok {
+		return []reference{r}
+	}
+
+	return nil
+}
+
+// expressionsConfig returns the configuration of the exprs with
+// the constant values and the references to resources
+// as '${aws_security_group.front.id}'. The shape is an example
+// of the values of the resource, from planShapes, used to know
+// which values are lists
+func (s *planScope) expressionsConfig(exprs map[string]interface{}, shape map[string]interface{}) map[string]interface{} {
+	cfg := make(map[string]interface{})
+	for k, e := range exprs {
+		sv := shape[k]
+		switch ev := e.(type) {
+		case []interface{}:
+			// The blocks are lists of expressions
+			// and all of them have the same shape
+			var bs map[string]interface{}
+			if sl, ok := sv.([]interface{}); ok && len(sl) != 0 {
+				bs, _ = sl[0].(map[string]interface{})
+			}
+			blocks := make([]interface{}, 0, len(ev))
+			for _, b := range ev {
+				bm, ok := b.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				blocks = append(blocks, s.expressionsConfig(bm, bs))
+			}
+			cfg[k] = blocks
+		case map[string]interface{}:
+			if cv, ok := ev["constant_value"]; ok {
+				cfg[k] = cv
+				continue
+			}
+
+			refs := make([]interface{}, 0)
+			seen := make(map[string]struct{})
+			for _, r := range s.resolveAll(expressionReferences(ev)) {
+				if r.attr == "" {
+					continue
+				}
+				ref := r.variable()
+				if _, ok := seen[ref]; ok {
+					continue
+				}
+				seen[ref] = struct{}{}
+				refs = append(refs, ref)
+			}
+
+			if len(refs) == 0 {
+				continue
+			}
+
+			// The type of the attribute is not on the plan so
+			// it's taken from the shape, and if it's not known
+			// more than one reference can only be a list
+			if _, ok := sv.([]interface{}); ok || (sv == nil && len(refs) > 1) {
+				cfg[k] = refs
+			} else {
+				cfg[k] = refs[0]
+			}
+		}
+	}
+	return cfg
+}
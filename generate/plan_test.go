@@ -0,0 +1,232 @@
+package generate_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/cycloidio/inframap/errcode"
+	"github.com/cycloidio/inframap/generate"
+	"github.com/cycloidio/inframap/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromPlan(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/aws_plan.json")
+		require.NoError(t, err)
+
+		g, err := generate.FromPlan(src, generate.Options{Clean: true, Connections: true, ExternalNodes: true})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				{
+					Canonical: "im_out.tcp/443->443",
+				},
+				{
+					Canonical: "aws_lb.front",
+				},
+				{
+					Canonical: "aws_db_instance.main",
+					Action:    graph.ActionReplace,
+				},
+				{
+					Canonical: "module.app.aws_instance.web",
+					Action:    graph.ActionCreate,
+				},
+				{
+					Canonical: "aws_elasticache_cluster.old",
+					Action:    graph.ActionDelete,
+				},
+			},
+			Edges: []*graph.Edge{
+				{
+					Source:     "im_out.tcp/443->443",
+					Target:     "aws_lb.front",
+					Canonicals: []string(nil),
+				},
+				{
+					Source:     "im_out.tcp/443->443",
+					Target:     "aws_elasticache_cluster.old",
+					Canonicals: []string(nil),
+				},
+				{
+					Source:     "aws_lb.front",
+					Target:     "module.app.aws_instance.web",
+					Canonicals: []string{"aws_security_group.lb", "aws_security_group.front"},
+					Ports:      []graph.Port{{Protocol: "tcp", FromPort: 80, ToPort: 80}},
+				},
+				{
+					Source:     "module.app.aws_instance.web",
+					Target:     "aws_db_instance.main",
+					Canonicals: []string{"aws_security_group.front", "aws_security_group.db"},
+					Ports:      []graph.Port{{Protocol: "tcp", FromPort: 3306, ToPort: 3306}},
+				},
+				{
+					Source:     "aws_elasticache_cluster.old",
+					Target:     "aws_lb.front",
+					Canonicals: []string{"aws_security_group.lb"},
+				},
+			},
+		}
+
+		assertEqualGraph(t, eg, g, nil)
+	})
+	t.Run("SuccessListFromShape", func(t *testing.T) {
+		// The 'vpc_security_group_ids' of the 'aws_instance.web' is unknown
+		// so it's known that it's a list from the 'aws_instance.bastion'
+		src := []byte(`{
+  "format_version": "1.0",
+  "planned_values": {
+    "root_module": {
+      "resources": [
+        {"address": "aws_security_group.web", "mode": "managed", "type": "aws_security_group", "name": "web", "values": {"name": "web", "vpc_id": "vpc-0a1b2c", "ingress": [], "egress": []}},
+        {"address": "aws_instance.web", "mode": "managed", "type": "aws_instance", "name": "web", "values": {"instance_type": "t3.small"}},
+        {"address": "aws_instance.bastion", "mode": "managed", "type": "aws_instance", "name": "bastion", "values": {"id": "i-0f1e2d3c", "vpc_security_group_ids": ["sg-0bastion"]}}
+      ]
+    }
+  },
+  "resource_changes": [
+    {"address": "aws_security_group.web", "mode": "managed", "type": "aws_security_group", "name": "web", "change": {"actions": ["create"], "before": null, "after_unknown": {"id": true}}},
+    {"address": "aws_instance.web", "mode": "managed", "type": "aws_instance", "name": "web", "change": {"actions": ["create"], "before": null, "after_unknown": {"id": true, "vpc_security_group_ids": true}}},
+    {"address": "aws_instance.bastion", "mode": "managed", "type": "aws_instance", "name": "bastion", "change": {"actions": ["no-op"], "before": {"id": "i-0f1e2d3c", "vpc_security_group_ids": ["sg-0bastion"]}, "after_unknown": {}}}
+  ],
+  "configuration": {
+    "root_module": {
+      "resources": [
+        {"mode": "managed", "type": "aws_security_group", "name": "web", "expressions": {"name": {"constant_value": "web"}, "vpc_id": {"constant_value": "vpc-0a1b2c"}}},
+        {"mode": "managed", "type": "aws_instance", "name": "web", "expressions": {"vpc_security_group_ids": {"references": ["aws_security_group.web[*].id", "aws_security_group.web"]}}},
+        {"mode": "managed", "type": "aws_instance", "name": "bastion", "expressions": {"vpc_security_group_ids": {"constant_value": ["sg-0bastion"]}}}
+      ]
+    }
+  }
+}`)
+
+		g, err := generate.FromPlan(src, generate.Options{})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+
+		n, err := g.GetNodeByCanonical("aws_instance.web")
+		require.NoError(t, err)
+		assert.Equal(t, "aws_vpc.vpc-0a1b2c", n.Group)
+	})
+	t.Run("SuccessModuleWithCount", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/aws_plan_module_count.json")
+		require.NoError(t, err)
+
+		g, err := generate.FromPlan(src, generate.Options{Instances: generate.InstancesCollapse})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				{
+					Canonical: "aws_db_instance.main",
+					Instances: 1,
+				},
+				{
+					Canonical: "module.app.aws_instance.web",
+					Action:    graph.ActionCreate,
+					Instances: 2,
+				},
+			},
+			Edges: []*graph.Edge{
+				{
+					Source: "module.app.aws_instance.web",
+					Target: "aws_db_instance.main",
+				},
+			},
+		}
+
+		assertEqualGraph(t, eg, g, nil)
+	})
+	t.Run("SuccessModuleWithCountExpand", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/aws_plan_module_count.json")
+		require.NoError(t, err)
+
+		g, err := generate.FromPlan(src, generate.Options{Instances: generate.InstancesExpand})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				{
+					Canonical: "aws_db_instance.main",
+				},
+				{
+					Canonical: "module.app[0].aws_instance.web",
+					Action:    graph.ActionCreate,
+				},
+				{
+					Canonical: "module.app[1].aws_instance.web",
+					Action:    graph.ActionCreate,
+				},
+			},
+			Edges: []*graph.Edge{
+				{
+					Source: "module.app[0].aws_instance.web",
+					Target: "aws_db_instance.main",
+				},
+				{
+					Source: "module.app[1].aws_instance.web",
+					Target: "aws_db_instance.main",
+				},
+			},
+		}
+
+		assertEqualGraph(t, eg, g, nil)
+	})
+	t.Run("SuccessState", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/aws_show_state.json")
+		require.NoError(t, err)
+
+		g, err := generate.FromPlan(src, generate.Options{Clean: true, Connections: true, ExternalNodes: true})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				{
+					Canonical: "module.app.aws_instance.web",
+				},
+				{
+					Canonical: "module.app.aws_db_instance.db",
+				},
+			},
+			Edges: []*graph.Edge{
+				{
+					Source:     "module.app.aws_instance.web",
+					Target:     "module.app.aws_db_instance.db",
+					Canonicals: []string{"aws_security_group.front"},
+				},
+			},
+		}
+
+		assertEqualGraph(t, eg, g, nil)
+	})
+	t.Run("ErrInvalidTFPlanFile", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/aws_state_sg.json")
+		require.NoError(t, err)
+
+		_, err = generate.FromPlan(src, generate.Options{})
+		assert.True(t, errors.Is(err, errcode.ErrInvalidTFPlanFile))
+	})
+}
+
+func TestIsPlan(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		for _, f := range []string{"aws_plan.json", "aws_show_state.json"} {
+			src, err := ioutil.ReadFile("./testdata/" + f)
+			require.NoError(t, err)
+			assert.True(t, generate.IsPlan(src), f)
+		}
+
+		src, err := ioutil.ReadFile("./testdata/aws_state_sg.json")
+		require.NoError(t, err)
+		assert.False(t, generate.IsPlan(src))
+		assert.False(t, generate.IsPlan([]byte(`resource "aws_lb" "front" {}`)))
+	})
+}
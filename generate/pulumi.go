@@ -0,0 +1,327 @@
+package generate
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/cycloidio/inframap/errcode"
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/provider/factory"
+	"github.com/cycloidio/inframap/state"
+)
+
+// pulumiExport is the JSON of 'pulumi stack export', or
+// the checkpoint of the stack on the file backends
+type pulumiExport struct {
+	Deployment *pulumiDeployment `json:"deployment"`
+	Checkpoint *struct {
+		Latest *pulumiDeployment `json:"latest"`
+	} `json:"checkpoint"`
+}
+
+type pulumiDeployment struct {
+	Resources []pulumiResource `json:"resources"`
+}
+
+type pulumiResource struct {
+	URN                  string                 `json:"urn"`
+	Custom               bool                   `json:"custom"`
+	Delete               bool                   `json:"delete"`
+	ID                   string                 `json:"id"`
+	Type                 string                 `json:"type"`
+	Inputs               map[string]interface{} `json:"inputs"`
+	Outputs              map[string]interface{} `json:"outputs"`
+	Parent               string                 `json:"parent"`
+	Dependencies         []string               `json:"dependencies"`
+	PropertyDependencies map[string][]string    `json:"propertyDependencies"`
+}
+
+// pulumiPackages holds as key the package of the Pulumi
+// types and as value the Terraform provider it bridges
+var pulumiPackages = map[string]string{
+	"aws":   "aws",
+	"gcp":   "google",
+	"azure": "azurerm",
+}
+
+// pulumiTypes holds as key the Pulumi types that can not be
+// guessed from the name and as value the Terraform resource type
+var pulumiTypes = map[string]string{
+	"aws:alb/loadBalancer:LoadBalancer":     "aws_alb",
+	"aws:elb/loadBalancer:LoadBalancer":     "aws_elb",
+	"aws:lb/loadBalancer:LoadBalancer":      "aws_lb",
+	"aws:rds/instance:Instance":             "aws_db_instance",
+	"aws:rds/subnetGroup:SubnetGroup":       "aws_db_subnet_group",
+	"aws:rds/parameterGroup:ParameterGroup": "aws_db_parameter_group",
+	"aws:s3/bucketV2:BucketV2":              "aws_s3_bucket",
+	"azure:lb/loadBalancer:LoadBalancer":    "azurerm_lb",
+}
+
+// reInvalidName matches the characters of the names
+// of Pulumi that can not be on a Canonical
+var reInvalidName = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// IsPulumi checks if the b is the JSON output of 'pulumi stack export',
+// or the checkpoint of a stack, which can be used with FromPulumi
+func IsPulumi(b []byte) bool {
+	var e pulumiExport
+	if err := json.Unmarshal(b, &e); err != nil {
+		return false
+	}
+
+	return e.Deployment != nil || (e.Checkpoint != nil && e.Checkpoint.Latest != nil)
+}
+
+// FromPulumi generates a graph.Graph from the JSON output of 'pulumi stack export'
+// applying the opt. The Pulumi types ('aws:ec2/instance:Instance') are the
+// Terraform resource types they bridge ('aws_instance'), the component
+// resources are modules and the dependencies and the propertyDependencies
+// are the Edges, then it's generated as a TFState
+func FromPulumi(b json.RawMessage, opt Options) (*graph.Graph, map[string]interface{}, error) {
+	st, err := pulumiState(b)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return fromStateStacks([]stateStack{{state: st}}, opt)
+}
+
+// pulumiState converts the b, the JSON of
+// 'pulumi stack export', to a state.State
+func pulumiState(b []byte) (*state.State, error) {
+	var e pulumiExport
+	if err := json.Unmarshal(b, &e); err != nil {
+		return nil, fmt.Errorf("%s: %w", err, errcode.ErrInvalidPulumiFile)
+	}
+
+	d := e.Deployment
+	if d == nil && e.Checkpoint != nil {
+		d = e.Checkpoint.Latest
+	}
+	if d == nil {
+		return nil, errcode.ErrInvalidPulumiFile
+	}
+
+	// modules holds as key the URN of the component
+	// resources and as value the module they are
+	modules := make(map[string]string)
+
+	// addrs holds as key the URN of the resources
+	// and as value the address they have
+	addrs := make(map[string]string)
+
+	// deps holds as key the Instance of each resource
+	// and as value the URNs of the ones it depends on
+	deps := make(map[*state.Instance][]string)
+
+	// names holds the addresses already used, as
+	// different names could be the same once the
+	// invalid characters are replaced ('web.1', 'web_1')
+	names := make(map[string]struct{})
+
+	resources := make([]*state.Resource, 0, len(d.Resources))
+	for _, pr := range d.Resources {
+		// The parents are always before
+		// the resources they have
+		module := modules[pr.Parent]
+
+		if pr.Delete || strings.HasPrefix(pr.Type, "pulumi:") {
+			continue
+		}
+
+		name := reInvalidName.ReplaceAllString(pulumiName(pr.URN), "_")
+		if !pr.Custom {
+			modules[pr.URN] = prefixWithModule(module, fmt.Sprintf("module.%s", uniqueName(names, module, "module", name)))
+			continue
+		}
+
+		// The external ones, read with the 'get' of the resources,
+		// are also managed as they are part of the infrastructure
+		rt := pulumiType(pr.Type)
+		r := &state.Resource{
+			Module: module,
+			Mode:   state.ModeManaged,
+			Type:   rt,
+			Name:   uniqueName(names, module, rt, name),
+		}
+
+		attrs := pr.Outputs
+		if attrs == nil {
+			attrs = pr.Inputs
+		}
+		i := &state.Instance{
+			Attributes: make(map[string]interface{}, len(attrs)+1),
+		}
+		for k, v := range pulumiAttributes(attrs).(map[string]interface{}) {
+			i.Attributes[k] = v
+		}
+		i.Attributes["id"] = pr.ID
+		r.Instances = []*state.Instance{i}
+
+		// The dependencies of the properties are
+		// also on the dependencies but not always
+		urns := append([]string{}, pr.Dependencies...)
+		props := make([]string, 0, len(pr.PropertyDependencies))
+		for p := range pr.PropertyDependencies {
+			props = append(props, p)
+		}
+		sort.Strings(props)
+		for _, p := range props {
+			urns = append(urns, pr.PropertyDependencies[p]...)
+		}
+
+		addrs[pr.URN] = prefixWithModule(module, r.Address())
+		deps[i] = urns
+		resources = append(resources, r)
+	}
+
+	// Once all the addresses are known the
+	// dependencies can be converted to them
+	for _, r := range resources {
+		for _, i := range r.Instances {
+			urns := deps[i]
+			seen := make(map[string]struct{})
+			i.Dependencies = make([]string, 0, len(urns))
+			for _, u := range urns {
+				addr, ok := addrs[u]
+				if !ok {
+					continue
+				}
+				if _, ok := seen[addr]; ok {
+					continue
+				}
+				seen[addr] = struct{}{}
+				i.Dependencies = append(i.Dependencies, addr)
+			}
+		}
+	}
+
+	return &state.State{Resources: resources}, nil
+}
+
+// uniqueName returns the name for the resource of the type rt on the module
+// that has not been used yet on the names, if it's already used a suffix
+// is added ('web_1_2'), and it's added to the names
+func uniqueName(names map[string]struct{}, module, rt, name string) string {
+	un := name
+	for i := 2; ; i++ {
+		addr := prefixWithModule(module, fmt.Sprintf("%s.%s", rt, un))
+		if _, ok := names[addr]; !ok {
+			names[addr] = struct{}{}
+			return un
+		}
+		un = fmt.Sprintf("%s_%d", name, i)
+	}
+}
+
+// pulumiName returns the name of the
+// resource, the last part of the urn
+func pulumiName(urn string) string {
+	parts := strings.Split(urn, "::")
+	return parts[len(parts)-1]
+}
+
+// pulumiType returns the Terraform resource type of the Pulumi pt, like
+// 'aws_instance' from 'aws:ec2/instance:Instance'. If it's not a known one
+// it tries with the module ('aws_ec2_instance') and without it ('aws_instance')
+// and if none of them is a resource of the provider the first one is used.
+// The Pulumi packages that do not bridge Terraform are like
+// 'kubernetes_service' from 'kubernetes:core/v1:Service'
+func pulumiType(pt string) string {
+	if t, ok := pulumiTypes[pt]; ok {
+		return t
+	}
+
+	parts := strings.Split(pt, ":")
+	if len(parts) != 3 {
+		return toSnakeCase(reInvalidName.ReplaceAllString(pt, "_"))
+	}
+
+	// The ones that do not bridge a Terraform
+	// provider only have the type ('Service')
+	pkg, ok := pulumiPackages[parts[0]]
+	if !ok {
+		return fmt.Sprintf("%s_%s", reInvalidName.ReplaceAllString(parts[0], "_"), toSnakeCase(parts[2]))
+	}
+
+	// The module and the name are like 'ec2/instance'
+	// and the 'index' module is the one by default
+	var module, name string
+	if i := strings.LastIndex(parts[1], "/"); i != -1 {
+		module, name = parts[1][:i], parts[1][i+1:]
+	} else {
+		name = parts[1]
+	}
+
+	candidates := make([]string, 0, 2)
+	if module != "" && module != "index" {
+		candidates = append(candidates, fmt.Sprintf("%s_%s_%s", pkg, reInvalidName.ReplaceAllString(module, "_"), toSnakeCase(name)))
+	}
+	candidates = append(candidates, fmt.Sprintf("%s_%s", pkg, toSnakeCase(name)))
+
+	for _, c := range candidates {
+		pv, rs, err := factory.GetProviderAndResource(c)
+		if err != nil {
+			break
+		}
+		if _, err := pv.Resource(rs); err == nil {
+			return c
+		}
+	}
+
+	return candidates[0]
+}
+
+// pulumiAttributes returns the v with the keys of the
+// properties in snake case, as the attributes of the TFState,
+// the keys that are not properties (like the tags) are kept
+func pulumiAttributes(v interface{}) interface{} {
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(vv))
+		for k, e := range vv {
+			// The properties always start
+			// with a lower case letter
+			if k != "" && unicode.IsLower(rune(k[0])) {
+				k = toSnakeCase(k)
+			}
+			// The keys of the tags are
+			// the ones of the user
+			if k == "tags" || k == "tags_all" {
+				res[k] = e
+				continue
+			}
+			res[k] = pulumiAttributes(e)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, 0, len(vv))
+		for _, e := range vv {
+			res = append(res, pulumiAttributes(e))
+		}
+		return res
+	}
+	return v
+}
+
+// toSnakeCase converts the s from camel
+// case 'securityGroupIds' to 'security_group_ids'
+func toSnakeCase(s string) string {
+	var sb strings.Builder
+	rs := []rune(s)
+	for i, r := range rs {
+		if unicode.IsUpper(r) {
+			// The acronyms like 'vpcID' are one word
+			if i != 0 && (unicode.IsLower(rs[i-1]) || unicode.IsDigit(rs[i-1]) || (i+1 < len(rs) && unicode.IsLower(rs[i+1]))) && rs[i-1] != '_' {
+				sb.WriteRune('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
@@ -0,0 +1,195 @@
+package generate_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/cycloidio/inframap/errcode"
+	"github.com/cycloidio/inframap/generate"
+	"github.com/cycloidio/inframap/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromPulumi(t *testing.T) {
+	t.Run("SuccessAWS", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/aws_pulumi_export.json")
+		require.NoError(t, err)
+
+		g, cfg, err := generate.FromPulumi(src, generate.Options{Clean: true, Connections: true, ExternalNodes: true, Attributes: []string{"engine"}})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+		require.NotNil(t, cfg)
+
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				{
+					Canonical: "module.lemp.aws_lb.front",
+				},
+				{
+					Canonical: "module.lemp.aws_launch_template.web",
+				},
+				{
+					Canonical:  "module.lemp.aws_db_instance.db",
+					Attributes: map[string]interface{}{"engine": "mysql"},
+				},
+				{
+					Canonical: "im_out.tcp/443->443",
+				},
+				{
+					Canonical: "im_out.tcp/80->80",
+				},
+			},
+			Edges: []*graph.Edge{
+				{
+					Source:     "im_out.tcp/80->80",
+					Target:     "module.lemp.aws_lb.front",
+					Canonicals: []string(nil),
+				},
+				{
+					Source:     "im_out.tcp/443->443",
+					Target:     "module.lemp.aws_lb.front",
+					Canonicals: []string(nil),
+				},
+				{
+					Source:     "module.lemp.aws_lb.front",
+					Target:     "module.lemp.aws_launch_template.web",
+					Canonicals: []string{"module.lemp.aws_security_group.front", "module.lemp.aws_security_group.web"},
+					Ports:      []graph.Port{{Protocol: "tcp", FromPort: 80, ToPort: 80}},
+				},
+				{
+					Source:     "module.lemp.aws_launch_template.web",
+					Target:     "module.lemp.aws_db_instance.db",
+					Canonicals: []string{"module.lemp.aws_security_group.web", "module.lemp.aws_security_group.db"},
+					Ports:      []graph.Port{{Protocol: "tcp", FromPort: 3306, ToPort: 3306}},
+				},
+			},
+		}
+
+		assertEqualGraph(t, eg, g, cfg)
+	})
+	t.Run("SuccessTags", func(t *testing.T) {
+		src := []byte(`{
+  "version": 3,
+  "deployment": {
+    "resources": [
+      {
+        "urn": "urn:pulumi:dev::web::aws:ec2/instance:Instance::web",
+        "custom": true,
+        "id": "i-0f1e2d3c",
+        "type": "aws:ec2/instance:Instance",
+        "outputs": {
+          "instanceType": "t3.small",
+          "tags": {"costCenter": "eng"},
+          "tagsAll": {"costCenter": "eng", "ManagedBy": "pulumi"}
+        }
+      }
+    ]
+  }
+}`)
+
+		g, cfg, err := generate.FromPulumi(src, generate.Options{Attributes: []string{"instance_type", "tags.costCenter", "tags_all.ManagedBy"}})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+		require.NotNil(t, cfg)
+
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				{
+					Canonical:  "aws_instance.web",
+					Attributes: map[string]interface{}{"instance_type": "t3.small", "tags.costCenter": "eng", "tags_all.ManagedBy": "pulumi"},
+				},
+			},
+			Edges: []*graph.Edge{},
+		}
+
+		assertEqualGraph(t, eg, g, cfg)
+	})
+	t.Run("SuccessRepeatedNamesAndExternal", func(t *testing.T) {
+		src := []byte(`{
+  "version": 3,
+  "deployment": {
+    "resources": [
+      {
+        "urn": "urn:pulumi:dev::web::aws:ec2/instance:Instance::legacy",
+        "custom": true,
+        "external": true,
+        "id": "i-0a0a0a0a",
+        "type": "aws:ec2/instance:Instance",
+        "outputs": {}
+      },
+      {
+        "urn": "urn:pulumi:dev::web::aws:ec2/instance:Instance::web.1",
+        "custom": true,
+        "id": "i-0b0b0b0b",
+        "type": "aws:ec2/instance:Instance",
+        "outputs": {},
+        "dependencies": ["urn:pulumi:dev::web::aws:ec2/instance:Instance::legacy"]
+      },
+      {
+        "urn": "urn:pulumi:dev::web::aws:ec2/instance:Instance::web_1",
+        "custom": true,
+        "id": "i-0c0c0c0c",
+        "type": "aws:ec2/instance:Instance",
+        "outputs": {},
+        "dependencies": ["urn:pulumi:dev::web::aws:ec2/instance:Instance::web.1"]
+      }
+    ]
+  }
+}`)
+
+		g, cfg, err := generate.FromPulumi(src, generate.Options{})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+		require.NotNil(t, cfg)
+
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				{
+					Canonical: "aws_instance.legacy",
+				},
+				{
+					Canonical: "aws_instance.web_1",
+				},
+				{
+					Canonical: "aws_instance.web_1_2",
+				},
+			},
+			Edges: []*graph.Edge{
+				{
+					Source: "aws_instance.web_1",
+					Target: "aws_instance.legacy",
+				},
+				{
+					Source: "aws_instance.web_1_2",
+					Target: "aws_instance.web_1",
+				},
+			},
+		}
+
+		assertEqualGraph(t, eg, g, cfg)
+	})
+	t.Run("ErrInvalidPulumiFile", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/aws_state_sg.json")
+		require.NoError(t, err)
+
+		_, _, err = generate.FromPulumi(src, generate.Options{})
+		assert.True(t, errors.Is(err, errcode.ErrInvalidPulumiFile))
+	})
+}
+
+func TestIsPulumi(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/aws_pulumi_export.json")
+		require.NoError(t, err)
+		assert.True(t, generate.IsPulumi(src))
+
+		for _, f := range []string{"aws_state_sg.json", "aws_plan.json"} {
+			src, err := ioutil.ReadFile("./testdata/" + f)
+			require.NoError(t, err)
+			assert.False(t, generate.IsPulumi(src), f)
+		}
+		assert.False(t, generate.IsPulumi([]byte(`resource "aws_lb" "front" {}`)))
+	})
+}
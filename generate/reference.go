@@ -0,0 +1,56 @@
+package generate
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// reIndex matches the keys of the instances
+// like '[0]' or '["key"]'
+var reIndex = regexp.MustCompile(`\[[^\]]*\]`)
+
+// reference is a reference to a
+// resource of the configuration
+type reference struct {
+	// path it's the module path
+	// of the resource like 'module.app'
+	path  string
+	rtype string
+	name  string
+
+	// attr is the attribute referenced, if any
+	attr string
+}
+
+// address returns the reference as
+// 'module.app.aws_lb.front'
+func (r reference) address() string {
+	return prefixWithModule(r.path, fmt.Sprintf("%s.%s", r.rtype, r.name))
+}
+
+// variable returns the reference as an interpolation
+// like '${module.app.aws_lb.front.id}', which
+// can be matched with reVariable
+func (r reference) variable() string {
+	return fmt.Sprintf("${%s.%s}", r.address(), r.attr)
+}
+
+// parseReference parses the parts of a reference ('aws_lb.front.id' split by '.')
+// made from the module path if it's a reference to a managed resource
+func parseReference(path string, parts []string) (reference, bool) {
+	if len(parts) < 2 {
+		return reference{}, false
+	}
+
+	switch parts[0] {
+	case "var", "local", "module", "data", "count", "each", "path", "terraform", "self":
+		return reference{}, false
+	}
+
+	r := reference{path: path, rtype: parts[0], name: parts[1]}
+	if len(parts) > 2 {
+		r.attr = parts[2]
+	}
+
+	return r, true
+}
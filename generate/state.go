@@ -1,42 +1,93 @@
 package generate
 
 import (
-	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
-	"github.com/cycloidio/flatmap"
 	"github.com/cycloidio/inframap/errcode"
 	"github.com/cycloidio/inframap/graph"
 	"github.com/cycloidio/inframap/provider"
 	"github.com/cycloidio/inframap/provider/factory"
-	"github.com/hashicorp/terraform/addrs"
-	"github.com/hashicorp/terraform/states/statefile"
+	"github.com/cycloidio/inframap/state"
 	uuid "github.com/satori/go.uuid"
 )
 
+// stackGroupType is the graph.Group.Type
+// of the stacks of FromStates
+const stackGroupType = "stack"
+
+// stateStack is the TFState of a stack
+type stateStack struct {
+	// name it's the name of the stack,
+	// empty if it's the only one
+	name  string
+	state *state.State
+}
+
+// prefix returns the prefix of the Canonicals
+// of the stack, like 'stack.network'
+func (s stateStack) prefix() string {
+	if s.name == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s.%s", stackGroupType, s.name)
+}
+
 // FromState generate a graph.Graph from the tfstate applying the opt
 func FromState(tfstate json.RawMessage, opt Options) (*graph.Graph, map[string]interface{}, error) {
-	// Since TF 0.13 'depends_on' has been dropped, so we do a manual
-	// replace from '"depends_on"' to '"dependencies"'
-	hasDependsOn := bytes.Contains(tfstate, []byte("\"depends_on\""))
-	tfstate = bytes.ReplaceAll(tfstate, []byte("\"depends_on\""), []byte("\"dependencies\""))
-	err := ValidateTFStateVersion(tfstate)
+	s, err := readStateStack("", tfstate)
 	if err != nil {
-		return nil, nil, fmt.Errorf("error while validating TFStateVersion: %w", err)
+		return nil, nil, err
 	}
 
-	buf := bytes.NewBuffer(tfstate)
+	return fromStateStacks([]stateStack{s}, opt)
+}
+
+// FromStates generates one graph.Graph from the tfstates of different stacks
+// applying the opt, the key is the name of each stack. Each stack is a
+// graph.Group and the Canonical of its Nodes are prefixed with it, like
+// 'stack.network.aws_vpc.main'. The resources with an attribute that has the ID
+// of a resource of another stack, like the ID of a security group, depend on it
+func FromStates(tfstates map[string]json.RawMessage, opt Options) (*graph.Graph, map[string]interface{}, error) {
+	names := make([]string, 0, len(tfstates))
+	for n := range tfstates {
+		if n == "" || strings.Contains(n, ".") {
+			return nil, nil, fmt.Errorf("stack %q: %w", n, errcode.ErrInvalidStackName)
+		}
+		names = append(names, n)
+	}
+	sort.Strings(names)
 
-	file, err := statefile.Read(buf)
+	stacks := make([]stateStack, 0, len(names))
+	for _, n := range names {
+		s, err := readStateStack(n, tfstates[n])
+		if err != nil {
+			return nil, nil, fmt.Errorf("stack %q: %w", n, err)
+		}
+		stacks = append(stacks, s)
+	}
+
+	return fromStateStacks(stacks, opt)
+}
+
+// readStateStack reads the tfstate of the stack with the name
+func readStateStack(name string, tfstate json.RawMessage) (stateStack, error) {
+	st, err := state.Read(tfstate)
 	if err != nil {
-		return nil, nil, fmt.Errorf("error while reading TFState: %w", err)
+		return stateStack{}, fmt.Errorf("error while reading TFState: %w", err)
 	}
 
-	migrateVersions(tfstate, file)
+	return stateStack{name: name, state: st}, nil
+}
+
+// fromStateStacks generates the graph.Graph of all the stacks
+func fromStateStacks(stacks []stateStack, opt Options) (*graph.Graph, map[string]interface{}, error) {
+	var err error
 
 	g := graph.New()
 
@@ -53,20 +104,55 @@ func FromState(tfstate json.RawMessage, opt Options) (*graph.Graph, map[string]i
 	// that we find on the TFState
 	nodeIDEdges := make(map[string][]string)
 
+	// nodeStacks holds as key the UUID (graph.Node.ID)
+	// and as value the name of the stack it's from
+	nodeStacks := make(map[string]string)
+
+	// addrKeys holds as key the address of the resource `aws_alb.front`
+	// and as value the keys of the instances that are Nodes, and
+	// nodeIDAddrKey holds the address and the key of each Node, so on
+	// expand the instances with the same keys are connected
+	addrKeys := make(map[string][]string)
+	nodeIDAddrKey := make(map[string][2]string)
+
+	// tfidGroups holds as key the ID of all the resources of
+	// the stacks, not only the Nodes, and as value the
+	// graph.Group of the stack in which they are
+	tfidGroups := make(map[string]string)
+
 	if !opt.Raw {
-		opt, err = checkProviders(file, opt)
+		opt, err = checkProviders(stacks, opt)
 		if err != nil {
 			return nil, nil, err
 		}
 	}
 
-	for _, m := range file.State.Modules {
-		for rk, rv := range m.Resources {
+	for _, s := range stacks {
+		if s.name != "" {
+			err = g.AddGroup(&graph.Group{
+				ID:   s.prefix(),
+				Name: s.name,
+				Type: stackGroupType,
+			})
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		for _, r := range s.state.Resources {
 			// If it's not a Resource we ignore it
-			if rv.Addr.Resource.Mode != addrs.ManagedResourceMode {
+			if r.Mode != state.ModeManaged {
 				continue
 			}
 
+			if s.name != "" {
+				for _, iv := range r.Instances {
+					if id, ok := iv.Attributes["id"].(string); ok {
+						tfidGroups[id] = s.prefix()
+					}
+				}
+			}
+
+			rk := r.Address()
 			pv, rs, err := getProviderAndResource(rk, opt)
 			if err != nil {
 				if errors.Is(err, errcode.ErrProviderNotFound) {
@@ -80,66 +166,42 @@ func FromState(tfstate json.RawMessage, opt Options) (*graph.Graph, map[string]i
 				continue
 			}
 
-			// The Instances is the representation of the
-			// 'count' on the Instance, could also be a 'for_each'
-			for id, iv := range rv.Instances {
-				if id != nil && id.String() != "[0]" {
-					continue
-				}
-				deps := make([]string, 0)
-				if len(iv.Current.Dependencies) != 0 {
-					deps = append(deps, instanceCurrentDependenciesToString(iv.Current.Dependencies)...)
-				}
-
-				// Dependencies will have reference to inside the module
-				// so we need to prefix them if needed to find the right node.
-				// This is only needed on the versions using `depends_on`
-				// for what we have found. The ones with `dependencies` have
-				// the right reference always.
-				if hasDependsOn {
-					for i, d := range deps {
-						if !strings.HasPrefix(d, "module.") {
-							deps[i] = prefixWithModule(m.Addr.String(), d)
-						}
-					}
+			// The Instances are the representation of the
+			// 'count' on the resource, could also be a 'for_each'
+			for _, iv := range nodeInstances(r.Instances, opt) {
+				// The dependencies are always inside of the stack
+				deps := make([]string, 0, len(iv.Dependencies))
+				for _, d := range iv.Dependencies {
+					deps = append(deps, prefixWithModule(s.prefix(), d))
 				}
 
-				aux := make(map[string]interface{})
-				if iv.Current.AttrsJSON != nil {
-					// For TF +0.12
-					err = json.Unmarshal(iv.Current.AttrsJSON, &aux)
-					if err != nil {
-						return nil, nil, fmt.Errorf("invalid fomrat JSON for resource %q with AttrsJSON %s: %w", string(iv.Current.AttrsJSON), rk, err)
-					}
-				} else {
-					// For TF 0.11
-					// AttrsFlat it's flatmap, so we'll use the flatmap.Expand
-					// to abstract the content, we'll first get the list of all
-					// the unique keys so then we can Expand each key
-					keys := make(map[string]struct{})
-					for k := range iv.Current.AttrsFlat {
-						kk := strings.Split(k, ".")
-						keys[kk[0]] = struct{}{}
-					}
-
-					for k := range keys {
-						aux[k] = flatmap.Expand(iv.Current.AttrsFlat, k)
-					}
+				aux := iv.Attributes
+				if aux == nil {
+					aux = make(map[string]interface{})
 				}
 
 				res, err := pv.Resource(rs)
 				if err != nil {
 					return nil, nil, err
 				}
-				tfid, ok := aux["id"]
+				tfid, ok := aux["id"].(string)
 				if !ok {
 					return nil, nil, fmt.Errorf("resource %q: %w", rk, errcode.ErrInvalidTFStateFileMissingResourceID)
 				}
+				addr := prefixWithModule(s.prefix(), prefixWithModule(r.Module, rk))
 				n := &graph.Node{
 					ID:        uuid.NewV4().String(),
-					Canonical: prefixWithModule(m.Addr.String(), rk),
-					TFID:      tfid.(string),
+					Canonical: addr,
+					TFID:      tfid,
 					Resource:  *res,
+					Group:     s.prefix(),
+				}
+
+				switch opt.Instances {
+				case InstancesExpand:
+					n.Canonical = addr + iv.Key
+				case InstancesCollapse:
+					n.Instances = len(r.Instances)
 				}
 
 				err = g.AddNode(n)
@@ -147,8 +209,16 @@ func FromState(tfstate json.RawMessage, opt Options) (*graph.Graph, map[string]i
 					return nil, nil, err
 				}
 
+				// The dependencies do not have the keys of the instances
+				// so each instance is also set on the address of the resource
 				nodeCanIDs[n.Canonical] = append(nodeCanIDs[n.Canonical], n.ID)
+				if addr != n.Canonical {
+					nodeCanIDs[addr] = append(nodeCanIDs[addr], n.ID)
+				}
+				addrKeys[addr] = append(addrKeys[addr], iv.Key)
+				nodeIDAddrKey[n.ID] = [2]string{addr, iv.Key}
 				nodeIDEdges[n.ID] = deps
+				nodeStacks[n.ID] = s.name
 				cfg[n.ID] = aux
 			}
 		}
@@ -156,7 +226,17 @@ func FromState(tfstate json.RawMessage, opt Options) (*graph.Graph, map[string]i
 
 	for sourceID, edges := range nodeIDEdges {
 		edgeIDs := make([]string, 0)
+		ak := nodeIDAddrKey[sourceID]
 		for _, e := range edges {
+			// If both resources have the same instances each one
+			// is only connected to the one with the same key
+			// ('aws_instance.web[0]' to 'aws_security_group.web[0]')
+			if opt.Instances == InstancesExpand && equalKeys(addrKeys[ak[0]], addrKeys[e]) {
+				if IDs, ok := nodeCanIDs[e+ak[1]]; ok {
+					edgeIDs = append(edgeIDs, IDs...)
+					continue
+				}
+			}
 			if IDs, ok := nodeCanIDs[e]; ok {
 				for _, nid := range IDs {
 					edgeIDs = append(edgeIDs, nid)
@@ -175,6 +255,19 @@ func FromState(tfstate json.RawMessage, opt Options) (*graph.Graph, map[string]i
 			}
 		}
 	}
+
+	if len(stacks) > 1 {
+		if err := addStackEdges(g, cfg, nodeStacks); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := addGroups(g, cfg, tfidGroups, opt); err != nil {
+		return nil, nil, err
+	}
+
+	addAttributes(g, cfg, opt)
+
 	// call the preprocess method for each
 	// TF provider in the file
 	if err := preprocess(g, cfg, opt); err != nil {
@@ -211,68 +304,6 @@ func FromState(tfstate json.RawMessage, opt Options) (*graph.Graph, map[string]i
 	return g, endCfg, nil
 }
 
-// migrateVersions will try to apply migrations of old
-// statefile:
-// * For version 3 will try to populate the Dependencies as they are
-//   removed by TF as they cannot be migrated from v3->v4
-func migrateVersions(src []byte, f *statefile.File) error {
-	version, err := tfStateVersion(src)
-	if err != nil {
-		return fmt.Errorf("could not read version: %w", err)
-	}
-
-	deps := make(map[string][]addrs.ConfigResource)
-
-	var state map[string]interface{}
-
-	if version == 3 {
-		err := json.Unmarshal(src, &state)
-		if err != nil {
-			return fmt.Errorf("error while reading TFState: %w", err)
-		}
-
-		if _, ok := state["modules"]; ok {
-			for _, m := range state["modules"].([]interface{}) {
-				rs, ok := m.(map[string]interface{})["resources"]
-				if !ok {
-					continue
-				}
-				for rn, rv := range rs.(map[string]interface{}) {
-					// As we have replaced from 'depends_on' to 'dependencies'
-					// we have to access it with 'dependencies'
-					dps, ok := rv.(map[string]interface{})["dependencies"]
-					if !ok && dps == nil {
-						continue
-					}
-					for _, d := range dps.([]interface{}) {
-						ds := strings.Split(d.(string), ".")
-						deps[rn] = append(deps[rn], addrs.ConfigResource{
-							Resource: addrs.Resource{
-								Mode: addrs.ManagedResourceMode,
-								Type: ds[0],
-								Name: ds[1],
-							},
-						})
-					}
-				}
-			}
-		}
-
-		for _, v := range f.State.Modules {
-			for rk, rv := range v.Resources {
-				for _, iv := range rv.Instances {
-					if d, ok := deps[rk]; ok {
-						iv.Current.Dependencies = d
-					}
-				}
-			}
-		}
-
-	}
-
-	return nil
-}
-
 // ValidateTFStateVersion validates that the version is the
 // one we support which is only 3 and 4
 func ValidateTFStateVersion(b []byte) error {
@@ -366,11 +397,10 @@ func buildConfig(g *graph.Graph, cfg map[string]map[string]interface{}, nodeCanI
 		}
 
 		// Canonical = module.name.aws_security_group.front-port80
-		// rt == path[-2] == resource Type ex: module.name.aws_security_group
-		// rn == path[-1] == resource Name ex: front-port80
-		path := strings.Split(n.Canonical, ".")
-		rn := path[len(path)-1]
-		rt := strings.Join(path[:len(path)-1], ".")
+		// rt == module and resource Type ex: module.name.aws_security_group
+		// rn == resource Name ex: front-port80, or front-port80[0] with the key
+		mod, rtype, rn := graph.ParseCanonical(n.Canonical)
+		rt := prefixWithModule(mod, rtype)
 
 		if _, ok := endCfg[rt]; !ok {
 			endCfg[rt] = make(map[string]interface{})
@@ -405,11 +435,10 @@ func buildConfig(g *graph.Graph, cfg map[string]map[string]interface{}, nodeCanI
 			}
 
 			// Canonical = module.name.aws_security_group.front-port80
-			// rt == path[-2] == resource Type ex: module.name.aws_security_group
-			// rn == path[-1] == resource Name ex: front-port80
-			path := strings.Split(can, ".")
-			rn := path[len(path)-1]
-			rt := strings.Join(path[:len(path)-1], ".")
+			// rt == module and resource Type ex: module.name.aws_security_group
+			// rn == resource Name ex: front-port80, or front-port80[0] with the key
+			mod, rtype, rn := graph.ParseCanonical(can)
+			rt := prefixWithModule(mod, rtype)
 
 			if _, ok := endCfg[rt]; !ok {
 				endCfg[rt] = make(map[string]interface{})
@@ -432,8 +461,9 @@ func buildConfig(g *graph.Graph, cfg map[string]map[string]interface{}, nodeCanI
 	return endCfg, nil
 }
 
-// reVariable matches ${aws_security_group.front.id}
-var reVariable = regexp.MustCompile(`\$\{(?P<type>[^\.][a-z0-9-_]+)\.(?P<name>[^\.][a-z0-9-_]+)\.(?P<attr>[a-z0-9-_]+)\}`)
+// reVariable matches ${aws_security_group.front.id} and, for
+// the resources inside of modules, ${module.app.aws_security_group.front.id}
+var reVariable = regexp.MustCompile(`\$\{(?P<module>(?:module\.[a-z0-9-_]+\.)*)(?P<type>[^\.][a-z0-9-_]+)\.(?P<name>[^\.][a-z0-9-_]+)\.(?P<attr>[a-z0-9-_]+)\}`)
 
 // fixEdges tries to fix the direction of the edges that was done based on the 'depends_on'
 // to something more Provider dependent by reading the actual config.
@@ -456,6 +486,31 @@ func fixEdges(g *graph.Graph, cfg map[string]map[string]interface{}, opt Options
 				imNodes[n.ID] = nodes
 			}
 
+			// The traffic allowed by each rule of the Edge is kept on
+			// the edge with the source of the rule, as it's where the
+			// traffic comes from, so when it's merged by 'mutate'
+			// the resulting edges have it
+			if rules := pv.ResourceRules(n.ID, rs, cfg); len(rules) != 0 {
+				for _, e := range edges {
+					oID := e.Source
+					if oID == n.ID {
+						oID = e.Target
+					}
+					on, err := g.GetNodeByID(oID)
+					if err != nil {
+						return err
+					}
+					for _, r := range rules {
+						for _, src := range r.Sources {
+							if isReference(on, src) {
+								e.AddPorts(r.Port)
+								break
+							}
+						}
+					}
+				}
+			}
+
 			// For the ins we have to check if any of the edges Target
 			// is this ID and reverse it because we want it to be the Source
 			for _, in := range ins {
@@ -470,7 +525,7 @@ func fixEdges(g *graph.Graph, cfg map[string]map[string]interface{}, opt Options
 							resMap[k] = res[0][i]
 						}
 					}
-					in = fmt.Sprintf("%s.%s", resMap["type"], resMap["name"])
+					in = fmt.Sprintf("%s%s.%s", resMap["module"], resMap["type"], resMap["name"])
 				}
 				for _, e := range edges {
 					rep, err := g.GetNodeByID(e.Target)
@@ -480,7 +535,7 @@ func fixEdges(g *graph.Graph, cfg map[string]map[string]interface{}, opt Options
 
 					if isID && rep.TFID == in {
 						g.InvertEdge(e.ID)
-					} else if !isID && rep.Canonical == in {
+					} else if !isID && reIndex.ReplaceAllString(rep.Canonical, "") == in {
 						g.InvertEdge(e.ID)
 					}
 				}
@@ -500,7 +555,7 @@ func fixEdges(g *graph.Graph, cfg map[string]map[string]interface{}, opt Options
 							resMap[k] = res[0][i]
 						}
 					}
-					out = fmt.Sprintf("%s.%s", resMap["type"], resMap["name"])
+					out = fmt.Sprintf("%s%s.%s", resMap["module"], resMap["type"], resMap["name"])
 				}
 				for _, e := range edges {
 					rep, err := g.GetNodeByID(e.Source)
@@ -510,7 +565,7 @@ func fixEdges(g *graph.Graph, cfg map[string]map[string]interface{}, opt Options
 
 					if isID && rep.TFID == out {
 						g.InvertEdge(e.ID)
-					} else if !isID && rep.Canonical == out {
+					} else if !isID && reIndex.ReplaceAllString(rep.Canonical, "") == out {
 						g.InvertEdge(e.ID)
 					}
 				}
@@ -587,6 +642,18 @@ func fixEdges(g *graph.Graph, cfg map[string]map[string]interface{}, opt Options
 	return nil
 }
 
+// isReference checks if the ref, which could be the TFID or
+// a variable like '${aws_security_group.front.id}', references the n
+func isReference(n *graph.Node, ref string) bool {
+	res := reVariable.FindStringSubmatch(ref)
+	if len(res) == 0 {
+		return n.TFID == ref
+	}
+
+	// The variables reference all the instances
+	return reIndex.ReplaceAllString(n.Canonical, "") == fmt.Sprintf("%s%s.%s", res[reVariable.SubexpIndex("module")], res[reVariable.SubexpIndex("type")], res[reVariable.SubexpIndex("name")])
+}
+
 // sumConnsDirection returns the total sum of all the
 // directions of the conns
 func sumConnsDirection(conns []*connection) int {
@@ -758,12 +825,45 @@ func mutate(g *graph.Graph, opt Options) error {
 	return mutate(g, opt)
 }
 
-func instanceCurrentDependenciesToString(deps []addrs.ConfigResource) []string {
-	res := make([]string, 0, len(deps))
-	for _, d := range deps {
-		res = append(res, d.String())
+// equalKeys checks if the keys of the instances
+// of two resources, that are sorted, are the same
+func equalKeys(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
 	}
-	return res
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// nodeInstances returns the instances that have to be Nodes
+// depending on the opt.Instances, all of them sorted if they
+// are expanded or only the first one if not
+func nodeInstances(instances []*state.Instance, opt Options) []*state.Instance {
+	is := make([]*state.Instance, len(instances))
+	copy(is, instances)
+
+	sort.SliceStable(is, func(i, j int) bool {
+		ki, kj := is[i].Key, is[j].Key
+		if ki == "" || kj == "" {
+			return ki == "" && kj != ""
+		}
+		ii, ierr := strconv.Atoi(strings.Trim(ki, "[]"))
+		ij, jerr := strconv.Atoi(strings.Trim(kj, "[]"))
+		if ierr == nil && jerr == nil {
+			return ii < ij
+		}
+		return ki < kj
+	})
+
+	if opt.Instances != InstancesExpand && len(is) > 1 {
+		is = is[:1]
+	}
+
+	return is
 }
 
 // getProviderAndResource uses factory.Options but if the opt.Raw is defined
@@ -778,7 +878,7 @@ func getProviderAndResource(rk string, opt Options) (provider.Provider, string,
 	if opt.Raw {
 		pv = provider.RawProvider{}
 
-		rss := strings.Split(rk, ".")
+		rss := strings.Split(reIndex.ReplaceAllString(rk, ""), ".")
 		if len(rss) > 1 {
 			rs = rss[len(rss)-2]
 		} else {
@@ -791,17 +891,17 @@ func getProviderAndResource(rk string, opt Options) (provider.Provider, string,
 	return pv, rs, err
 }
 
-// checkProviders checks if we support any of the Providers from f, if not it'll set
+// checkProviders checks if we support any of the Providers from stacks, if not it'll set
 // the opt.Raw to true so it can be used with Raw instead of returning an empty Graph
-func checkProviders(f *statefile.File, opt Options) (Options, error) {
-	for _, v := range f.State.Modules {
-		for rk, rv := range v.Resources {
+func checkProviders(stacks []stateStack, opt Options) (Options, error) {
+	for _, s := range stacks {
+		for _, r := range s.state.Resources {
 			// If it's not a Resource we ignore it
-			if rv.Addr.Resource.Mode != addrs.ManagedResourceMode {
+			if r.Mode != state.ModeManaged {
 				continue
 			}
 
-			_, _, err := getProviderAndResource(rk, opt)
+			_, _, err := getProviderAndResource(r.Address(), opt)
 			if err != nil {
 				if errors.Is(err, errcode.ErrProviderNotFound) {
 					continue
@@ -859,6 +959,198 @@ func preprocess(g *graph.Graph, cfg map[string]map[string]interface{}, opt Optio
 	return nil
 }
 
+// addGroups adds to g the Groups in which each Node is based on
+// the cfg. As the same Group (ex: a region) could be inside of different
+// Groups (ex: networks), the ID of each Group is the path of all of them
+// like 'google_compute_network.default/region.europe-west1'. If the Node
+// is already on a Group (ex: a stack) they are inside of it, except if the
+// Group is a resource with a Group on the tfidGroups, the stack that owns it
+func addGroups(g *graph.Graph, cfg map[string]map[string]interface{}, tfidGroups map[string]string, opt Options) error {
+	for _, n := range g.Nodes {
+		pv, rs, err := getProviderAndResource(n.Canonical, opt)
+		if err != nil {
+			return err
+		}
+
+		parent := n.Group
+		for i, pg := range pv.ResourceGroups(n.ID, rs, cfg) {
+			// From HCL the Group could be a reference
+			// like '${aws_vpc.main.id}' so we only
+			// keep the name of the resource 'main'
+			if res := reVariable.FindStringSubmatch(pg.ID); len(res) != 0 {
+				pg.ID = res[reVariable.SubexpIndex("name")]
+			}
+
+			// The outermost Group is inside of the Group of the
+			// resource that it is, the stack that owns it, and
+			// not of the Node that is on it
+			if sg, ok := tfidGroups[pg.ID]; ok && i == 0 {
+				parent = sg
+			}
+
+			id := fmt.Sprintf("%s.%s", pg.Type, pg.ID)
+			if parent != "" {
+				id = fmt.Sprintf("%s/%s", parent, id)
+			}
+
+			if _, err := g.GetGroupByID(id); err != nil {
+				err = g.AddGroup(&graph.Group{
+					ID:     id,
+					Name:   pg.ID,
+					Type:   pg.Type,
+					Parent: parent,
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			parent = id
+		}
+
+		n.Group = parent
+	}
+
+	return nil
+}
+
+// addStackEdges connects the Nodes of g that have on the cfg the ID
+// of a Node of another stack, the nodeStacks are the stack of each Node
+func addStackEdges(g *graph.Graph, cfg map[string]map[string]interface{}, nodeStacks map[string]string) error {
+	// tfidNodes holds as key the graph.Node.TFID
+	// and as value the Nodes with it
+	tfidNodes := make(map[string][]*graph.Node)
+	for _, n := range g.Nodes {
+		if n.TFID != "" {
+			tfidNodes[n.TFID] = append(tfidNodes[n.TFID], n)
+		}
+	}
+
+	for _, n := range g.Nodes {
+		for k, v := range cfg[n.ID] {
+			// The 'id' is the one of the Node
+			if k == "id" {
+				continue
+			}
+			for _, s := range stackReferences(k, v) {
+				for _, tn := range tfidNodes[s] {
+					if nodeStacks[tn.ID] == nodeStacks[n.ID] {
+						continue
+					}
+					err := g.AddEdge(&graph.Edge{
+						ID:     uuid.NewV4().String(),
+						Source: n.ID,
+						Target: tn.ID,
+					})
+					if err != nil && !errors.Is(err, errcode.ErrGraphAlreadyExistsEdge) {
+						return err
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// reProviderID matches the IDs that are generated by the providers, like the
+// ARNs, the AWS ones ('sg-0a1b2c3d'), the Google self links or the Azure IDs
+var reProviderID = regexp.MustCompile(`^(arn:|https://www\.googleapis\.com/|projects/|/subscriptions/|[a-z]+-[0-9a-f]{8,17}$)`)
+
+// stackReferences returns the strings of v, the value of the attribute k,
+// that could be the ID of a resource of another stack. The tags are ignored
+// and the strings have to be on an attribute that references other
+// resources ('vpc_id', 'security_groups') or look like a provider ID
+func stackReferences(k string, v interface{}) []string {
+	if k == "tags" || k == "tags_all" {
+		return nil
+	}
+
+	switch vv := v.(type) {
+	case string:
+		if isReferenceAttribute(k) || reProviderID.MatchString(vv) {
+			return []string{vv}
+		}
+	case []interface{}:
+		res := make([]string, 0)
+		for _, e := range vv {
+			res = append(res, stackReferences(k, e)...)
+		}
+		return res
+	case map[string]interface{}:
+		res := make([]string, 0)
+		for ek, e := range vv {
+			res = append(res, stackReferences(ek, e)...)
+		}
+		return res
+	}
+	return nil
+}
+
+// isReferenceAttribute checks if the attribute k
+// is one that has the ID of other resources
+func isReferenceAttribute(k string) bool {
+	for _, sfx := range []string{"_id", "_ids", "security_groups", "arn", "arns"} {
+		if strings.HasSuffix(k, sfx) {
+			return true
+		}
+	}
+	return false
+}
+
+// addAttributes keeps on each Node of the g the
+// opt.Attributes that it has on the cfg
+func addAttributes(g *graph.Graph, cfg map[string]map[string]interface{}, opt Options) {
+	if len(opt.Attributes) == 0 {
+		return
+	}
+
+	for _, n := range g.Nodes {
+		for _, path := range opt.Attributes {
+			v, ok := getAttribute(cfg[n.ID], strings.Split(path, "."))
+			if !ok {
+				continue
+			}
+			if n.Attributes == nil {
+				n.Attributes = make(map[string]interface{})
+			}
+			n.Attributes[path] = v
+		}
+	}
+}
+
+// getAttribute returns the value of the path on the v, the lists
+// can be indexed ('ebs_block_device.0.volume_size') or if not their
+// first element is used ('network_interface.network')
+func getAttribute(v interface{}, path []string) (interface{}, bool) {
+	for _, k := range path {
+		if l, ok := v.([]interface{}); ok {
+			if i, err := strconv.Atoi(k); err == nil {
+				if i < 0 || i >= len(l) {
+					return nil, false
+				}
+				v = l[i]
+				continue
+			}
+			if len(l) == 0 {
+				return nil, false
+			}
+			v = l[0]
+		}
+
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		if v, ok = m[k]; !ok {
+			return nil, false
+		}
+	}
+
+	return v, true
+}
+
 // prefixWithModule will check if it has to prefix and do so if needed
 func prefixWithModule(moduleName, resource string) string {
 	if moduleName != "" {
@@ -1,7 +1,9 @@
 package generate_test
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"testing"
 
@@ -127,11 +129,13 @@ func TestFromState_AWS(t *testing.T) {
 					Source:     "module.lemp.aws_lb.tQBgz",
 					Target:     "module.lemp.aws_launch_template.vIkyE",
 					Canonicals: []string{"module.lemp.aws_security_group.rZnGI", "module.lemp.aws_security_group.YPHPR"},
+					Ports:      []graph.Port{{Protocol: "tcp", FromPort: 80, ToPort: 80}},
 				},
 				{
 					Source:     "module.lemp.aws_launch_template.vIkyE",
 					Target:     "module.lemp.aws_db_instance.Cpbzf",
 					Canonicals: []string{"module.lemp.aws_security_group.YPHPR", "module.lemp.aws_security_group.LHwFh"},
+					Ports:      []graph.Port{{Protocol: "tcp", FromPort: 3306, ToPort: 3306}},
 				},
 			},
 		}
@@ -201,6 +205,50 @@ func TestFromState_AWS(t *testing.T) {
 		assertEqualGraph(t, eg, g, cfg)
 	})
 
+	t.Run("SuccessSGMultipleRules", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/aws_state_sg_rules.json")
+		require.NoError(t, err)
+
+		g, cfg, err := generate.FromState(src, generate.Options{Clean: true, Connections: true, ExternalNodes: true})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+		require.NotNil(t, cfg)
+
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				{
+					Canonical: "aws_instance.bastion",
+				},
+				{
+					Canonical: "aws_instance.web",
+				},
+				{
+					Canonical: "im_out.tcp/443->443",
+				},
+			},
+			Edges: []*graph.Edge{
+				{
+					Source:     "im_out.tcp/443->443",
+					Target:     "aws_instance.web",
+					Canonicals: []string(nil),
+				},
+				{
+					Source:     "im_out.tcp/443->443",
+					Target:     "aws_instance.bastion",
+					Canonicals: []string{"aws_security_group.bastion"},
+				},
+				{
+					Source:     "aws_instance.bastion",
+					Target:     "aws_instance.web",
+					Canonicals: []string{"aws_security_group.bastion", "aws_security_group.web"},
+					Ports:      []graph.Port{{Protocol: "tcp", FromPort: 22, ToPort: 22}},
+				},
+			},
+		}
+
+		assertEqualGraph(t, eg, g, cfg)
+	})
+
 	t.Run("MultipleHangingEdges", func(t *testing.T) {
 		src, err := ioutil.ReadFile("./testdata/aws_state_multiple_hanging_edges.json")
 		require.NoError(t, err)
@@ -287,6 +335,139 @@ func TestFromState_AWS(t *testing.T) {
 		require.NotNil(t, cfg)
 		assert.Len(t, g.Nodes, 2)
 	})
+	t.Run("WithForEach", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/aws_state_with_for_each.json")
+		require.NoError(t, err)
+
+		g, cfg, err := generate.FromState(src, generate.Options{Clean: true, Connections: true, ExternalNodes: true})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+		require.NotNil(t, cfg)
+
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				{
+					Canonical: "flexibleengine_blockstorage_volume_v2.data",
+				},
+				{
+					Canonical: "flexibleengine_compute_instance_v2.web",
+				},
+			},
+			Edges: []*graph.Edge{
+				{
+					Source: "flexibleengine_compute_instance_v2.web",
+					Target: "flexibleengine_blockstorage_volume_v2.data",
+				},
+			},
+		}
+
+		assertEqualGraph(t, eg, g, cfg)
+		n, err := g.GetNodeByCanonical("flexibleengine_blockstorage_volume_v2.data")
+		require.NoError(t, err)
+		assert.Equal(t, "2a7d9e1f-6b3c-4a8d-9f0e-1c2b3a4d5e6f", n.TFID)
+	})
+	t.Run("SuccessInstancesExpand", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/aws_state_with_for_each.json")
+		require.NoError(t, err)
+
+		g, cfg, err := generate.FromState(src, generate.Options{Clean: true, Connections: true, ExternalNodes: true, Instances: generate.InstancesExpand})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+		require.NotNil(t, cfg)
+
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				{
+					Canonical: `flexibleengine_blockstorage_volume_v2.data["eu.west"]`,
+				},
+				{
+					Canonical: `flexibleengine_blockstorage_volume_v2.data["us"]`,
+				},
+				{
+					Canonical: "flexibleengine_compute_instance_v2.web[0]",
+				},
+				{
+					Canonical: "flexibleengine_compute_instance_v2.web[1]",
+				},
+				{
+					Canonical: "flexibleengine_compute_instance_v2.web[2]",
+				},
+			},
+			Edges: make([]*graph.Edge, 0),
+		}
+
+		for _, w := range []string{"0", "1", "2"} {
+			for _, d := range []string{`"eu.west"`, `"us"`} {
+				eg.Edges = append(eg.Edges, &graph.Edge{
+					Source: "flexibleengine_compute_instance_v2.web[" + w + "]",
+					Target: "flexibleengine_blockstorage_volume_v2.data[" + d + "]",
+				})
+			}
+		}
+
+		assertEqualGraph(t, eg, g, cfg)
+	})
+	t.Run("SuccessInstancesExpandSameKeys", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/aws_state_expand_count.json")
+		require.NoError(t, err)
+
+		g, cfg, err := generate.FromState(src, generate.Options{Clean: true, Connections: true, ExternalNodes: true, Instances: generate.InstancesExpand})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+		require.NotNil(t, cfg)
+
+		eg := &graph.Graph{
+			Nodes: make([]*graph.Node, 0),
+			Edges: make([]*graph.Edge, 0),
+		}
+
+		// Each instance is only connected to
+		// the one with the same key
+		for i := 0; i < 10; i++ {
+			k := fmt.Sprintf("[%d]", i)
+			eg.Nodes = append(eg.Nodes,
+				&graph.Node{Canonical: "flexibleengine_blockstorage_volume_v2.data" + k},
+				&graph.Node{Canonical: "flexibleengine_compute_instance_v2.web" + k},
+			)
+			eg.Edges = append(eg.Edges, &graph.Edge{
+				Source: "flexibleengine_compute_instance_v2.web" + k,
+				Target: "flexibleengine_blockstorage_volume_v2.data" + k,
+			})
+		}
+
+		assertEqualGraph(t, eg, g, cfg)
+		assert.Len(t, g.Edges, 10)
+	})
+	t.Run("SuccessInstancesCollapse", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/aws_state_with_for_each.json")
+		require.NoError(t, err)
+
+		g, cfg, err := generate.FromState(src, generate.Options{Clean: true, Connections: true, ExternalNodes: true, Instances: generate.InstancesCollapse})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+		require.NotNil(t, cfg)
+
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				{
+					Canonical: "flexibleengine_blockstorage_volume_v2.data",
+					Instances: 2,
+				},
+				{
+					Canonical: "flexibleengine_compute_instance_v2.web",
+					Instances: 3,
+				},
+			},
+			Edges: []*graph.Edge{
+				{
+					Source: "flexibleengine_compute_instance_v2.web",
+					Target: "flexibleengine_blockstorage_volume_v2.data",
+				},
+			},
+		}
+
+		assertEqualGraph(t, eg, g, cfg)
+	})
 	t.Run("Version3", func(t *testing.T) {
 		src, err := ioutil.ReadFile("./testdata/version_3_state.json")
 		require.NoError(t, err)
@@ -300,6 +481,7 @@ func TestFromState_AWS(t *testing.T) {
 			Nodes: []*graph.Node{
 				{
 					Canonical: "module.cycloid.aws_alb.front",
+					Group:     "aws_vpc.vpc-5972c43f",
 				},
 				{
 					Canonical: "module.cycloid.aws_cloudfront_distribution.cdn",
@@ -309,15 +491,18 @@ func TestFromState_AWS(t *testing.T) {
 				},
 				{
 					Canonical: "module.cycloid.aws_instance.batch",
+					Group:     "aws_vpc.vpc-5972c43f/aws_subnet.subnet-4caca117",
 				},
 				{
 					Canonical: "module.cycloid.aws_ebs_volume.flux",
 				},
 				{
 					Canonical: "module.cycloid.aws_db_instance.website",
+					Group:     "aws_vpc.vpc-5972c43f",
 				},
 				{
 					Canonical: "module.cycloid.aws_elasticache_cluster.redis",
+					Group:     "aws_vpc.vpc-5972c43f",
 				},
 				{
 					Canonical: "im_out.tcp/443->443",
@@ -639,6 +824,18 @@ func TestFromState_Google(t *testing.T) {
 
 		assertEqualGraph(t, eg, g, cfg)
 	})
+	t.Run("SuccessWithAttributes", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/google_state.json")
+		require.NoError(t, err)
+
+		g, _, err := generate.FromState(src, generate.Options{Clean: true, Connections: true, ExternalNodes: true, Attributes: []string{"tags.0", "tags.1"}})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+
+		n, err := g.GetNodeByCanonical("google_compute_instance.ZthAT")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"tags.0": "ssh"}, n.Attributes)
+	})
 }
 
 func TestFromState_Azure(t *testing.T) {
@@ -678,3 +875,125 @@ func TestFromState_Azure(t *testing.T) {
 		assertEqualGraph(t, eg, g, cfg)
 	})
 }
+
+func TestFromStates(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		network, err := ioutil.ReadFile("./testdata/stacks/network.tfstate")
+		require.NoError(t, err)
+		app, err := ioutil.ReadFile("./testdata/stacks/app.tfstate")
+		require.NoError(t, err)
+
+		g, cfg, err := generate.FromStates(map[string]json.RawMessage{"network": network, "app": app}, generate.Options{Clean: true, Connections: true, ExternalNodes: true})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+		require.NotNil(t, cfg)
+
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				{
+					Canonical: "im_out.tcp/443->443",
+				},
+				{
+					Canonical: "stack.network.aws_lb.front",
+					Group:     "stack.network/aws_vpc.vpc-0a1b2c",
+				},
+				{
+					Canonical: "stack.app.aws_instance.web",
+					Group:     "stack.network/aws_vpc.vpc-0a1b2c",
+				},
+				{
+					Canonical: "stack.app.aws_db_instance.db",
+					Group:     "stack.network/aws_vpc.vpc-0a1b2c",
+				},
+			},
+			Edges: []*graph.Edge{
+				{
+					Source:     "im_out.tcp/443->443",
+					Target:     "stack.network.aws_lb.front",
+					Canonicals: []string(nil),
+				},
+				{
+					Source:     "stack.network.aws_lb.front",
+					Target:     "stack.app.aws_instance.web",
+					Canonicals: []string{"stack.network.aws_security_group.lb", "stack.network.aws_security_group.app"},
+					Ports:      []graph.Port{{Protocol: "tcp", FromPort: 80, ToPort: 80}},
+				},
+				{
+					Source:     "stack.app.aws_instance.web",
+					Target:     "stack.app.aws_db_instance.db",
+					Canonicals: []string{"stack.network.aws_security_group.app", "stack.app.aws_security_group.db"},
+					Ports:      []graph.Port{{Protocol: "tcp", FromPort: 5432, ToPort: 5432}},
+				},
+			},
+		}
+
+		assertEqualGraph(t, eg, g, cfg)
+
+		gr, err := g.GetGroupByID("stack.app")
+		require.NoError(t, err)
+		assert.Equal(t, &graph.Group{ID: "stack.app", Name: "app", Type: "stack"}, gr)
+
+		gr, err = g.GetGroupByID("stack.network/aws_vpc.vpc-0a1b2c")
+		require.NoError(t, err)
+		assert.Equal(t, "stack.network", gr.Parent)
+
+		_, err = g.GetGroupByID("stack.app/aws_vpc.vpc-0a1b2c")
+		assert.True(t, errors.Is(err, errcode.ErrGraphNotFoundGroup))
+	})
+	t.Run("SuccessNotReferences", func(t *testing.T) {
+		logs := []byte(`{
+  "version": 4,
+  "terraform_version": "0.14.11",
+  "resources": [
+    {
+      "mode": "managed",
+      "type": "aws_s3_bucket",
+      "name": "logs",
+      "provider": "provider.aws",
+      "instances": [{"attributes": {"id": "logs", "bucket": "logs"}}]
+    }
+  ]
+}`)
+		app := []byte(`{
+  "version": 4,
+  "terraform_version": "0.14.11",
+  "resources": [
+    {
+      "mode": "managed",
+      "type": "aws_instance",
+      "name": "web",
+      "provider": "provider.aws",
+      "instances": [{"attributes": {"id": "i-0f1e2d3c", "tags": {"Name": "logs"}, "tags_all": {"Name": "logs"}, "user_data": "logs"}}]
+    }
+  ]
+}`)
+
+		g, cfg, err := generate.FromStates(map[string]json.RawMessage{"logs": logs, "app": app}, generate.Options{})
+		require.NoError(t, err)
+		require.NotNil(t, g)
+		require.NotNil(t, cfg)
+
+		eg := &graph.Graph{
+			Nodes: []*graph.Node{
+				{
+					Canonical: "stack.logs.aws_s3_bucket.logs",
+					Group:     "stack.logs",
+				},
+				{
+					Canonical: "stack.app.aws_instance.web",
+					Group:     "stack.app",
+				},
+			},
+			Edges: []*graph.Edge{},
+		}
+
+		assertEqualGraph(t, eg, g, cfg)
+	})
+	t.Run("ErrInvalidStackName", func(t *testing.T) {
+		network, err := ioutil.ReadFile("./testdata/stacks/network.tfstate")
+		require.NoError(t, err)
+
+		_, _, err = generate.FromStates(map[string]json.RawMessage{"net.work": network}, generate.Options{})
+		assert.True(t, errors.Is(err, errcode.ErrInvalidStackName))
+	})
+}
@@ -0,0 +1,17 @@
+variable "app_security_group_id" {}
+
+resource "aws_security_group" "db" {
+  name = "db"
+
+  ingress {
+    from_port       = 3306
+    to_port         = 3306
+    protocol        = "tcp"
+    security_groups = [var.app_security_group_id]
+  }
+}
+
+resource "aws_db_instance" "main" {
+  instance_class         = "db.t3.micro"
+  vpc_security_group_ids = [aws_security_group.db.id]
+}
@@ -0,0 +1,32 @@
+resource "aws_security_group" "lb" {
+  name = "lb"
+
+  ingress {
+    from_port   = 443
+    to_port     = 443
+    protocol    = "tcp"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+}
+
+resource "aws_lb" "front" {
+  name            = "front"
+  security_groups = [aws_security_group.lb.id]
+}
+
+module "app" {
+  source = "./modules/app"
+
+  lb_security_group_id = aws_security_group.lb.id
+}
+
+module "db" {
+  source  = "cycloidio/db/aws"
+  version = "1.0.0"
+
+  app_security_group_id = module.app.security_group_id
+}
+
+module "remote" {
+  source = "cycloidio/not-installed/aws"
+}
@@ -0,0 +1,25 @@
+variable "lb_security_group_id" {}
+
+locals {
+  security_groups = [aws_security_group.app.id]
+}
+
+resource "aws_security_group" "app" {
+  name = "app"
+
+  ingress {
+    from_port       = 80
+    to_port         = 80
+    protocol        = "tcp"
+    security_groups = [var.lb_security_group_id]
+  }
+}
+
+resource "aws_instance" "web" {
+  instance_type          = "t3.micro"
+  vpc_security_group_ids = local.security_groups
+}
+
+output "security_group_id" {
+  value = aws_security_group.app.id
+}
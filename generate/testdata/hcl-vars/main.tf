@@ -0,0 +1,51 @@
+variable "allowed" {
+  type    = list(string)
+  default = ["10.0.0.0/8"]
+}
+
+variable "port" {
+  type    = number
+  default = 80
+}
+
+locals {
+  ports    = [var.port, local.tls_port]
+  tls_port = 443
+}
+
+resource "aws_security_group" "lb" {
+  name = "lb"
+
+  ingress {
+    from_port   = local.ports[0]
+    to_port     = local.ports[0]
+    protocol    = "tcp"
+    cidr_blocks = var.allowed
+  }
+
+  ingress {
+    from_port   = local.tls_port
+    to_port     = local.tls_port
+    protocol    = "tcp"
+    cidr_blocks = var.allowed
+  }
+}
+
+resource "aws_lb" "front" {
+  security_groups = [aws_security_group.lb.id]
+}
+
+resource "aws_security_group" "app" {
+  name = "app"
+
+  ingress {
+    from_port       = var.port
+    to_port         = var.port
+    protocol        = "tcp"
+    security_groups = [aws_security_group.lb.id]
+  }
+}
+
+resource "aws_instance" "app" {
+  vpc_security_group_ids = [aws_security_group.app.id]
+}
@@ -0,0 +1,156 @@
+package generate
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform/configs"
+	"github.com/spf13/afero"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// readRootVars returns the values of the variables of the root module
+// from the 'terraform.tfvars' and '*.auto.tfvars' on the dir, the
+// opt.VarFiles and the opt.Vars in that order of priority, as Terraform does
+func readRootVars(fs afero.Fs, parser *configs.Parser, dir string, mod *configs.Module, opt Options) (map[string]cty.Value, error) {
+	vars := make(map[string]cty.Value)
+
+	files := make([]string, 0)
+	for _, n := range []string{"terraform.tfvars", "terraform.tfvars.json"} {
+		if ok, _ := afero.Exists(fs, filepath.Join(dir, n)); ok {
+			files = append(files, filepath.Join(dir, n))
+		}
+	}
+
+	fis, err := afero.ReadDir(fs, dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	auto := make([]string, 0)
+	for _, fi := range fis {
+		if !fi.IsDir() && (strings.HasSuffix(fi.Name(), ".auto.tfvars") || strings.HasSuffix(fi.Name(), ".auto.tfvars.json")) {
+			auto = append(auto, filepath.Join(dir, fi.Name()))
+		}
+	}
+	sort.Strings(auto)
+
+	files = append(files, auto...)
+	files = append(files, opt.VarFiles...)
+
+	for _, f := range files {
+		vals, diags := parser.LoadValuesFile(f)
+		if diags.HasErrors() {
+			return nil, errors.New(diags.Error())
+		}
+		for k, v := range vals {
+			vars[k] = v
+		}
+	}
+
+	for k, v := range opt.Vars {
+		// As Terraform, the ones not declared are ignored
+		vr, ok := mod.Variables[k]
+		if !ok {
+			continue
+		}
+		val, diags := vr.ParsingMode.Parse(k, v)
+		if diags.HasErrors() {
+			return nil, errors.New(diags.Error())
+		}
+		vars[k] = val
+	}
+
+	return vars, nil
+}
+
+// buildEvalContext sets the context used to evaluate the expressions of m,
+// and of all the modules it calls, with the vars and the default values
+// of the variables and the locals. The values that can not be
+// known, like the attributes of the resources, are unknown and
+// the files used on the functions are read from the fs
+func (m *hclModule) buildEvalContext(fs afero.Fs, vars map[string]cty.Value) {
+	variables := make(map[string]cty.Value, len(m.mod.Variables))
+	for name, v := range m.mod.Variables {
+		val, ok := vars[name]
+		if !ok {
+			val = v.Default
+		}
+		if val == cty.NilVal {
+			val = cty.DynamicVal
+		}
+		if v.Type != cty.NilType && v.Type != cty.DynamicPseudoType {
+			if cv, err := convert.Convert(val, v.Type); err == nil {
+				val = cv
+			}
+		}
+		variables[name] = val
+	}
+
+	m.ctx = &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"var": cty.ObjectVal(variables),
+			"path": cty.ObjectVal(map[string]cty.Value{
+				"module": cty.StringVal(m.dir),
+				"root":   cty.StringVal(m.root().dir),
+				"cwd":    cty.StringVal("."),
+			}),
+		},
+		Functions: functions(fs, m.dir),
+	}
+
+	// The locals can reference other locals so they are
+	// evaluated until all the ones that can be are known
+	locals := make(map[string]cty.Value, len(m.mod.Locals))
+	for name := range m.mod.Locals {
+		locals[name] = cty.DynamicVal
+	}
+	for i := 0; i <= len(m.mod.Locals); i++ {
+		m.ctx.Variables["local"] = cty.ObjectVal(locals)
+
+		changed := false
+		for name, l := range m.mod.Locals {
+			if locals[name].IsWhollyKnown() {
+				continue
+			}
+			val, diags := l.Expr.Value(m.ctx)
+			if diags.HasErrors() || !val.IsWhollyKnown() {
+				continue
+			}
+			locals[name] = val
+			changed = true
+		}
+		if !changed {
+			break
+		}
+	}
+	m.ctx.Variables["local"] = cty.ObjectVal(locals)
+
+	for _, c := range m.children {
+		// The variables of the modules called are
+		// the arguments of the call on m
+		cvars := make(map[string]cty.Value)
+		attrs, _ := c.call.Config.JustAttributes()
+		for name, attr := range attrs {
+			val, diags := attr.Expr.Value(m.ctx)
+			if diags.HasErrors() {
+				val = cty.DynamicVal
+			}
+			cvars[name] = val
+		}
+		c.buildEvalContext(fs, cvars)
+	}
+}
+
+// root returns the module that calls all the others
+func (m *hclModule) root() *hclModule {
+	for m.parent != nil {
+		m = m.parent
+	}
+	return m
+}
@@ -14,6 +14,14 @@ type Edge struct {
 	// do not repeat them
 	mCanonicals map[string]struct{}
 
+	// Ports is the traffic allowed
+	// through the Edge
+	Ports []Port
+	// mPorts is used to know which Ports
+	// are already on the Ports slice so we
+	// do not repeat them
+	mPorts map[Port]struct{}
+
 	Source string
 	Target string
 }
@@ -45,3 +53,18 @@ func (e *Edge) AddCanonicals(cans ...string) {
 		}
 	}
 }
+
+// AddPorts adds the ports to the internal list, if
+// one is repeated it'll be ignored
+func (e *Edge) AddPorts(ports ...Port) {
+	if e.mPorts == nil {
+		e.mPorts = make(map[Port]struct{})
+	}
+
+	for _, p := range ports {
+		if _, ok := e.mPorts[p]; !ok {
+			e.mPorts[p] = struct{}{}
+			e.Ports = append(e.Ports, p)
+		}
+	}
+}
@@ -76,3 +76,24 @@ func TestAddCanonicals(t *testing.T) {
 		assert.Equal(t, []string{"a", "b", "c"}, e.Canonicals)
 	})
 }
+
+func TestAddPorts(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		e := graph.Edge{}
+		p80 := graph.Port{Protocol: "tcp", FromPort: 80, ToPort: 80}
+		p443 := graph.Port{Protocol: "tcp", FromPort: 443, ToPort: 443}
+		e.AddPorts(p80)
+		assert.Equal(t, []graph.Port{p80}, e.Ports)
+		e.AddPorts(p80, p443)
+		assert.Equal(t, []graph.Port{p80, p443}, e.Ports)
+	})
+}
+
+func TestPortString(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		assert.Equal(t, "tcp/443", graph.Port{Protocol: "tcp", FromPort: 443, ToPort: 443}.String())
+		assert.Equal(t, "udp/8000-8080", graph.Port{Protocol: "udp", FromPort: 8000, ToPort: 8080}.String())
+		assert.Equal(t, "icmp", graph.Port{Protocol: "icmp", FromPort: -1, ToPort: -1}.String())
+		assert.Equal(t, "all", graph.Port{Protocol: "-1"}.String())
+	})
+}
@@ -8,8 +8,9 @@ import (
 
 // Graph defines the standard format of a Graph
 type Graph struct {
-	Edges []*Edge
-	Nodes []*Node
+	Edges  []*Edge
+	Nodes  []*Node
+	Groups []*Group
 
 	// nodesCans canonical -> struct{}{}
 	nodesCans map[string]*Node
@@ -26,6 +27,9 @@ type Graph struct {
 
 	// edgesIDs id -> struct{}{}
 	edgesIDs map[string]*Edge
+
+	// groupsIDs id -> *Group
+	groupsIDs map[string]*Group
 }
 
 // New returns a new initialized Graph
@@ -35,6 +39,7 @@ func New() *Graph {
 		nodesIDs:          make(map[string]*Node),
 		edgesSourceTarget: make(map[string]*Edge),
 		edgesIDs:          make(map[string]*Edge),
+		groupsIDs:         make(map[string]*Group),
 
 		nodesWithEdge: make(map[string][]*Edge),
 	}
@@ -105,6 +110,39 @@ func (g *Graph) AddNode(n *Node) error {
 	return nil
 }
 
+// AddGroup adds a Group to the Graph, the
+// Parent, if any, has to be already on the Graph
+func (g *Graph) AddGroup(gr *Group) error {
+	if gr.ID == "" {
+		return errcode.ErrGraphRequiredGroupID
+	}
+
+	if _, ok := g.groupsIDs[gr.ID]; ok {
+		return fmt.Errorf("with ID %q: %w", gr.ID, errcode.ErrGraphAlreadyExistsGroup)
+	}
+
+	if gr.Parent != "" {
+		if _, ok := g.groupsIDs[gr.Parent]; !ok {
+			return fmt.Errorf("with ID %q: %w", gr.Parent, errcode.ErrGraphNotFoundGroupParent)
+		}
+	}
+
+	g.groupsIDs[gr.ID] = gr
+
+	g.Groups = append(g.Groups, gr)
+
+	return nil
+}
+
+// GetGroupByID returns the requested Group with the gID
+func (g *Graph) GetGroupByID(gID string) (*Group, error) {
+	gr, ok := g.groupsIDs[gID]
+	if !ok {
+		return nil, errcode.ErrGraphNotFoundGroup
+	}
+	return gr, nil
+}
+
 // GetNodeByID returns the requested Node with the nID
 func (g *Graph) GetNodeByID(nID string) (*Node, error) {
 	n, ok := g.nodesIDs[nID]
@@ -192,6 +230,7 @@ func (g *Graph) Replace(srcID, repID string) error {
 		}
 
 		e.AddCanonicals(append(mutualEdge.Canonicals, srcNode.Canonical)...)
+		e.AddPorts(mutualEdge.Ports...)
 
 		ee, okstt := g.edgesSourceTarget[e.Source+e.Target]
 
@@ -202,8 +241,9 @@ func (g *Graph) Replace(srcID, repID string) error {
 			g.edgesSourceTarget[e.Source+e.Target] = e
 		} else {
 			// Before removing repeated edges we add the
-			// canonicals from the edge we want to delete
+			// canonicals and ports from the edge we want to delete
 			ee.AddCanonicals(e.Canonicals...)
+			ee.AddPorts(e.Ports...)
 			g.removeEdgeByID(e.ID)
 		}
 	}
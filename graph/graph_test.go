@@ -1,6 +1,7 @@
 package graph_test
 
 import (
+	"errors"
 	"sort"
 	"testing"
 
@@ -398,6 +399,37 @@ func TestNodeReplace(t *testing.T) {
 
 		assert.Len(t, g.Edges, 1)
 	})
+	t.Run("SuccessWithPorts", func(t *testing.T) {
+		g := graph.New()
+		n1 := &graph.Node{ID: "1", Canonical: "can1"}
+		n2 := &graph.Node{ID: "2", Canonical: "can2"}
+		n3 := &graph.Node{ID: "3", Canonical: "can3"}
+		p80 := graph.Port{Protocol: "tcp", FromPort: 80, ToPort: 80}
+		p443 := graph.Port{Protocol: "tcp", FromPort: 443, ToPort: 443}
+		p22 := graph.Port{Protocol: "tcp", FromPort: 22, ToPort: 22}
+		e1 := &graph.Edge{ID: "1", Source: n1.ID, Target: n2.ID}
+		e1.AddPorts(p80)
+		e2 := &graph.Edge{ID: "2", Source: n2.ID, Target: n3.ID}
+		e2.AddPorts(p443)
+		e3 := &graph.Edge{ID: "3", Source: n1.ID, Target: n3.ID}
+		e3.AddPorts(p22)
+
+		require.NoError(t, g.AddNode(n1))
+		require.NoError(t, g.AddNode(n2))
+		require.NoError(t, g.AddNode(n3))
+		require.NoError(t, g.AddEdge(e1))
+		require.NoError(t, g.AddEdge(e2))
+		require.NoError(t, g.AddEdge(e3))
+
+		err := g.Replace("2", "3")
+		require.NoError(t, err)
+
+		// The e1 is merged into the e3 as it
+		// would be a repeated edge
+		require.Len(t, g.Edges, 1)
+		assert.Equal(t, e3.ID, g.Edges[0].ID)
+		assert.Equal(t, []graph.Port{p22, p80, p443}, g.Edges[0].Ports)
+	})
 	t.Run("SuccessWithMultipleEdgesToReconnect", func(t *testing.T) {
 		g := graph.New()
 		n1 := &graph.Node{ID: "1", Canonical: "can1"}
@@ -624,3 +656,44 @@ func TestInvertEdge(t *testing.T) {
 		}, g.Edges)
 	})
 }
+
+func TestAddGroup(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		g := graph.New()
+		g1 := &graph.Group{ID: "1", Name: "vpc", Type: "aws_vpc"}
+		g2 := &graph.Group{ID: "2", Name: "subnet", Type: "aws_subnet", Parent: "1"}
+
+		err := g.AddGroup(g1)
+		require.NoError(t, err)
+
+		err = g.AddGroup(g2)
+		require.NoError(t, err)
+
+		assert.Equal(t, []*graph.Group{g1, g2}, g.Groups)
+
+		gr, err := g.GetGroupByID("2")
+		require.NoError(t, err)
+		assert.Equal(t, g2, gr)
+	})
+	t.Run("RequiredGroupID", func(t *testing.T) {
+		g := graph.New()
+
+		err := g.AddGroup(&graph.Group{Name: "vpc"})
+		assert.True(t, errors.Is(err, errcode.ErrGraphRequiredGroupID))
+	})
+	t.Run("AlreadyExistsGroup", func(t *testing.T) {
+		g := graph.New()
+
+		err := g.AddGroup(&graph.Group{ID: "1"})
+		require.NoError(t, err)
+
+		err = g.AddGroup(&graph.Group{ID: "1"})
+		assert.True(t, errors.Is(err, errcode.ErrGraphAlreadyExistsGroup))
+	})
+	t.Run("NotFoundGroupParent", func(t *testing.T) {
+		g := graph.New()
+
+		err := g.AddGroup(&graph.Group{ID: "1", Parent: "2"})
+		assert.True(t, errors.Is(err, errcode.ErrGraphNotFoundGroupParent))
+	})
+}
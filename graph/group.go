@@ -0,0 +1,20 @@
+package graph
+
+// Group defines a container of Nodes, like a VPC
+// or a region, that can be inside of another Group
+type Group struct {
+	// ID it's the unique identifier of the Group
+	// on the Graph
+	ID string
+
+	// Name it's the identifier the Group has on
+	// the Provider, ex: 'vpc-0a1b2c' or 'europe-west1'
+	Name string
+
+	// Type it's the kind of Group, ex: 'aws_vpc'
+	Type string
+
+	// Parent it's the ID of the Group in which
+	// this one is, if any
+	Parent string
+}
@@ -1,6 +1,19 @@
 package graph
 
-import "github.com/cycloidio/tfdocs/resource"
+import (
+	"strings"
+
+	"github.com/cycloidio/tfdocs/resource"
+)
+
+// List of the Actions that a Node can have
+// when it's generated from a plan
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionReplace = "replace"
+)
 
 // Node defines the standard format of an Edge
 type Node struct {
@@ -10,7 +23,8 @@ type Node struct {
 	// Canonical it's 'aws_lb.front' format
 	Canonical string
 
-	// Position holds the position of the node if any
+	// Position holds the position (center) of the node if any,
+	// it is set when the Graph is laid out by the layout package
 	Position []int // x, y
 
 	// TFID it's the internal ID it has on TF
@@ -23,4 +37,74 @@ type Node struct {
 	// Weight is the addition of the Directions
 	// of the Node
 	Weight int
+
+	// Group it's the ID of the Group in
+	// which the Node is, if any
+	Group string
+
+	// Attributes are the attributes of the resource
+	// kept by the generator, the key is the path
+	// of the attribute like 'tags.Name'
+	Attributes map[string]interface{}
+
+	// Action it's the change that the plan will
+	// do to the resource (ActionCreate, ActionUpdate,
+	// ActionDelete or ActionReplace), empty if none
+	Action string
+
+	// Instances it's the number of instances ('count'
+	// or 'for_each') of the resource when they are
+	// collapsed into this Node, 0 if they are not
+	Instances int
+}
+
+// ParseCanonical splits the can ('module.app.aws_lb.front') into
+// the module path ('module.app'), the resource type ('aws_lb') and
+// the name ('front'). The dots inside of the keys
+// of the instances ('aws_lb.front["eu.west"]') are ignored and
+// the stack ('stack.network.aws_vpc.main') is part of the module path
+func ParseCanonical(can string) (module, rtype, name string) {
+	parts := splitCanonical(can)
+
+	// Each module is 'module.NAME', or the stack 'stack.NAME',
+	// and after them we still need the type and the name
+	var i int
+	if len(parts) > 3 && parts[0] == "stack" {
+		i = 2
+	}
+	for i+3 < len(parts) && parts[i] == "module" {
+		i += 2
+	}
+
+	module = strings.Join(parts[:i], ".")
+	rtype = parts[i]
+	if i+1 < len(parts) {
+		name = strings.Join(parts[i+1:], ".")
+	}
+
+	return module, rtype, name
+}
+
+// splitCanonical splits the can by '.' but ignoring
+// the ones inside of '[]'
+func splitCanonical(can string) []string {
+	parts := make([]string, 0)
+	var (
+		depth int
+		start int
+	)
+	for i, r := range can {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '.':
+			if depth == 0 {
+				parts = append(parts, can[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, can[start:])
 }
@@ -0,0 +1,35 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCanonical(t *testing.T) {
+	tests := []struct {
+		Name      string
+		Canonical string
+		Module    string
+		Type      string
+		RName     string
+	}{
+		{Name: "Resource", Canonical: "aws_lb.front", Type: "aws_lb", RName: "front"},
+		{Name: "Module", Canonical: "module.app.aws_lb.front", Module: "module.app", Type: "aws_lb", RName: "front"},
+		{Name: "NestedModules", Canonical: "module.app.module.db.aws_db_instance.main", Module: "module.app.module.db", Type: "aws_db_instance", RName: "main"},
+		{Name: "External", Canonical: "im_out.tcp/443->443", Type: "im_out", RName: "tcp/443->443"},
+		{Name: "Instance", Canonical: `module.app["eu.west"].aws_instance.web["eu.west"]`, Module: `module.app["eu.west"]`, Type: "aws_instance", RName: `web["eu.west"]`},
+		{Name: "Stack", Canonical: "stack.network.module.vpc.aws_vpc.main", Module: "stack.network.module.vpc", Type: "aws_vpc", RName: "main"},
+		{Name: "OnlyType", Canonical: "aws_lb", Type: "aws_lb"},
+		{Name: "ModuleResource", Canonical: "module.aws_lb", Type: "module", RName: "aws_lb"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			m, rt, n := graph.ParseCanonical(tt.Canonical)
+			assert.Equal(t, tt.Module, m)
+			assert.Equal(t, tt.Type, rt)
+			assert.Equal(t, tt.RName, n)
+		})
+	}
+}
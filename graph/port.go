@@ -0,0 +1,36 @@
+package graph
+
+import "fmt"
+
+// Port is the traffic that is allowed
+// through an Edge
+type Port struct {
+	// Protocol it's the protocol, ex: 'tcp', if
+	// it's '-1' or 'all' it means all of them
+	Protocol string
+
+	// FromPort it's the start of the range of ports
+	FromPort int
+
+	// ToPort it's the end of the range of ports
+	ToPort int
+}
+
+// String returns the Port in the 'tcp/443'
+// or 'tcp/8000-8080' format, if it has no
+// ports (ex: icmp) only the Protocol is returned
+func (p Port) String() string {
+	if p.Protocol == "-1" || p.Protocol == "all" {
+		return "all"
+	}
+
+	if p.FromPort <= 0 && p.ToPort <= 0 {
+		return p.Protocol
+	}
+
+	if p.FromPort == p.ToPort {
+		return fmt.Sprintf("%s/%d", p.Protocol, p.FromPort)
+	}
+
+	return fmt.Sprintf("%s/%d-%d", p.Protocol, p.FromPort, p.ToPort)
+}
@@ -0,0 +1,4 @@
+// Package layout has the logic to calculate the
+// position of the Nodes of a graph.Graph so it can
+// be drawn without any external tool
+package layout
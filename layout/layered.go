@@ -0,0 +1,375 @@
+package layout
+
+import (
+	"sort"
+
+	"github.com/cycloidio/inframap/graph"
+)
+
+// sweeps is the number of iterations done to
+// reduce the crossings between layers
+const sweeps = 8
+
+// Layout is the result of the Layered
+type Layout struct {
+	// Width and Height are the total size
+	// of the laid out Graph
+	Width  int
+	Height int
+
+	// Edges holds the graph.Edge.ID -> [][x, y] with all the
+	// points the Edge goes through, from the Source to the Target
+	Edges map[string][][]int
+}
+
+// vertex is a Node of the layered graph, it can
+// be a graph.Node or a dummy vertex used to split
+// the edges that go through multiple layers
+type vertex struct {
+	// node is nil for the dummy vertices
+	node *graph.Node
+
+	layer int
+	pos   int
+
+	succ []int
+	pred []int
+
+	x, y int
+}
+
+// Layered lays out g in layers from top to bottom following the direction of the
+// Edges (Sugiyama style) and sets the graph.Node.Position (center of the Node) of
+// each Node of g.
+// The steps are:
+// * Remove the cycles by inverting the Edges that close them
+// * Assign a layer to each Node (longest path)
+// * Split the Edges that cross multiple layers with dummy vertices
+// * Reduce the crossings with the barycenter heuristic
+// * Assign the coordinates
+func Layered(g *graph.Graph, opt Options) *Layout {
+	l := &Layout{
+		Edges: make(map[string][][]int),
+	}
+
+	if len(g.Nodes) == 0 {
+		return l
+	}
+
+	// nodeIdx holds graph.Node.ID -> index of vertices
+	nodeIdx := make(map[string]int, len(g.Nodes))
+	vertices := make([]*vertex, 0, len(g.Nodes))
+	for i, n := range g.Nodes {
+		nodeIdx[n.ID] = i
+		vertices = append(vertices, &vertex{node: n})
+	}
+
+	reversed := removeCycles(g, nodeIdx)
+
+	// edges holds the edges (already without cycles) that
+	// are used for the layering, self loops are ignored
+	edges := make(map[string][2]int)
+	for _, e := range g.Edges {
+		src, tr := nodeIdx[e.Source], nodeIdx[e.Target]
+		if src == tr {
+			continue
+		}
+		if _, ok := reversed[e.ID]; ok {
+			src, tr = tr, src
+		}
+		edges[e.ID] = [2]int{src, tr}
+		vertices[src].succ = append(vertices[src].succ, tr)
+		vertices[tr].pred = append(vertices[tr].pred, src)
+	}
+
+	assignLayers(vertices)
+
+	// chains holds graph.Edge.ID -> all the vertices
+	// the edge goes through from the top to the bottom
+	chains := make(map[string][]int, len(edges))
+	for _, e := range g.Edges {
+		se, ok := edges[e.ID]
+		if !ok {
+			continue
+		}
+		src, tr := se[0], se[1]
+		chain := []int{src}
+		prev := src
+		for ly := vertices[src].layer + 1; ly < vertices[tr].layer; ly++ {
+			d := len(vertices)
+			vertices = append(vertices, &vertex{layer: ly})
+			replaceLink(vertices, prev, tr, d)
+			chain = append(chain, d)
+			prev = d
+		}
+		chains[e.ID] = append(chain, tr)
+	}
+
+	layers := buildLayers(vertices)
+	orderLayers(vertices, layers)
+
+	// Assign the coordinates, each layer is centered
+	// with the widest one
+	var maxLen int
+	for _, ly := range layers {
+		if len(ly) > maxLen {
+			maxLen = len(ly)
+		}
+	}
+	slot := opt.NodeWidth + opt.NodeSpacing
+	for li, ly := range layers {
+		offset := (maxLen - len(ly)) * slot / 2
+		for i, vi := range ly {
+			v := vertices[vi]
+			v.x = offset + i*slot + opt.NodeWidth/2
+			v.y = li*(opt.NodeHeight+opt.LayerSpacing) + opt.NodeHeight/2
+			if v.node != nil {
+				v.node.Position = []int{v.x, v.y}
+			}
+		}
+	}
+
+	l.Width = maxLen*slot - opt.NodeSpacing
+	l.Height = len(layers)*(opt.NodeHeight+opt.LayerSpacing) - opt.LayerSpacing
+
+	for _, e := range g.Edges {
+		chain, ok := chains[e.ID]
+		if !ok {
+			// It's a self loop
+			v := vertices[nodeIdx[e.Source]]
+			l.Edges[e.ID] = [][]int{{v.x, v.y}, {v.x, v.y}}
+			continue
+		}
+		points := make([][]int, 0, len(chain))
+		for _, vi := range chain {
+			points = append(points, []int{vertices[vi].x, vertices[vi].y})
+		}
+		// If it was reversed the points have to go
+		// from the actual Source to the Target
+		if _, ok := reversed[e.ID]; ok {
+			for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+				points[i], points[j] = points[j], points[i]
+			}
+		}
+		l.Edges[e.ID] = points
+	}
+
+	return l
+}
+
+// removeCycles returns the graph.Edge.ID of the Edges that
+// have to be reversed so g does not have any cycle, it's
+// calculated with a DFS in which all the back edges
+// are the ones closing a cycle
+func removeCycles(g *graph.Graph, nodeIdx map[string]int) map[string]struct{} {
+	const (
+		white = iota
+		gray
+		black
+	)
+
+	reversed := make(map[string]struct{})
+	colors := make([]int, len(g.Nodes))
+
+	var visit func(n *graph.Node)
+	visit = func(n *graph.Node) {
+		colors[nodeIdx[n.ID]] = gray
+		for _, e := range g.GetEdgesForNode(n.ID) {
+			if e.Source != n.ID || e.Target == n.ID {
+				continue
+			}
+			ti := nodeIdx[e.Target]
+			switch colors[ti] {
+			case gray:
+				reversed[e.ID] = struct{}{}
+			case white:
+				visit(g.Nodes[ti])
+			}
+		}
+		colors[nodeIdx[n.ID]] = black
+	}
+
+	for i, n := range g.Nodes {
+		if colors[i] == white {
+			visit(n)
+		}
+	}
+
+	return reversed
+}
+
+// assignLayers assigns the layer to each vertex using the longest
+// path from the sources, after that the sources are moved down
+// to be just over the closest successor so they do not
+// end all on the first layer
+func assignLayers(vertices []*vertex) {
+	indeg := make([]int, len(vertices))
+	for _, v := range vertices {
+		for _, s := range v.succ {
+			indeg[s]++
+		}
+	}
+
+	order := make([]int, 0, len(vertices))
+	for i := range vertices {
+		if indeg[i] == 0 {
+			order = append(order, i)
+		}
+	}
+	for i := 0; i < len(order); i++ {
+		v := vertices[order[i]]
+		for _, s := range v.succ {
+			if v.layer+1 > vertices[s].layer {
+				vertices[s].layer = v.layer + 1
+			}
+			indeg[s]--
+			if indeg[s] == 0 {
+				order = append(order, s)
+			}
+		}
+	}
+
+	for i := len(order) - 1; i >= 0; i-- {
+		v := vertices[order[i]]
+		if len(v.pred) != 0 || len(v.succ) == 0 {
+			continue
+		}
+		min := -1
+		for _, s := range v.succ {
+			if min == -1 || vertices[s].layer < min {
+				min = vertices[s].layer
+			}
+		}
+		v.layer = min - 1
+	}
+}
+
+// replaceLink replaces the link from src to tr
+// for a link from src to d and from d to tr
+func replaceLink(vertices []*vertex, src, tr, d int) {
+	for i, s := range vertices[src].succ {
+		if s == tr {
+			vertices[src].succ[i] = d
+			break
+		}
+	}
+	for i, p := range vertices[tr].pred {
+		if p == src {
+			vertices[tr].pred[i] = d
+			break
+		}
+	}
+	vertices[d].pred = []int{src}
+	vertices[d].succ = []int{tr}
+}
+
+// buildLayers returns the vertices grouped by layer
+func buildLayers(vertices []*vertex) [][]int {
+	var max int
+	for _, v := range vertices {
+		if v.layer > max {
+			max = v.layer
+		}
+	}
+
+	layers := make([][]int, max+1)
+	for i, v := range vertices {
+		v.pos = len(layers[v.layer])
+		layers[v.layer] = append(layers[v.layer], i)
+	}
+
+	return layers
+}
+
+// orderLayers reorders the vertices of each layer to
+// reduce the crossings using the barycenter of the
+// connected vertices on the previous (or next) layer.
+// It keeps the order with less crossings
+func orderLayers(vertices []*vertex, layers [][]int) {
+	best := copyLayers(layers)
+	bestCrossings := crossings(vertices, layers)
+
+	for i := 0; i < sweeps && bestCrossings > 0; i++ {
+		if i%2 == 0 {
+			for l := 1; l < len(layers); l++ {
+				sortByBarycenter(vertices, layers[l], func(v *vertex) []int { return v.pred })
+			}
+		} else {
+			for l := len(layers) - 2; l >= 0; l-- {
+				sortByBarycenter(vertices, layers[l], func(v *vertex) []int { return v.succ })
+			}
+		}
+
+		if c := crossings(vertices, layers); c < bestCrossings {
+			bestCrossings = c
+			best = copyLayers(layers)
+		}
+	}
+
+	for l, ly := range best {
+		layers[l] = ly
+		for i, vi := range ly {
+			vertices[vi].pos = i
+		}
+	}
+}
+
+// sortByBarycenter sorts the layer by the barycenter of the
+// position of the vertices returned by adj, if a vertex
+// has none it'll keep the current position
+func sortByBarycenter(vertices []*vertex, layer []int, adj func(v *vertex) []int) {
+	bary := make(map[int]float64, len(layer))
+	for _, vi := range layer {
+		v := vertices[vi]
+		vs := adj(v)
+		if len(vs) == 0 {
+			bary[vi] = float64(v.pos)
+			continue
+		}
+		var sum int
+		for _, a := range vs {
+			sum += vertices[a].pos
+		}
+		bary[vi] = float64(sum) / float64(len(vs))
+	}
+
+	sort.SliceStable(layer, func(i, j int) bool {
+		return bary[layer[i]] < bary[layer[j]]
+	})
+
+	for i, vi := range layer {
+		vertices[vi].pos = i
+	}
+}
+
+// crossings returns the number of crossings between
+// all the consecutive layers
+func crossings(vertices []*vertex, layers [][]int) int {
+	var res int
+	for l := 0; l < len(layers)-1; l++ {
+		type link struct{ src, tr int }
+		links := make([]link, 0)
+		for _, vi := range layers[l] {
+			for _, s := range vertices[vi].succ {
+				links = append(links, link{src: vertices[vi].pos, tr: vertices[s].pos})
+			}
+		}
+		for i := 0; i < len(links); i++ {
+			for j := i + 1; j < len(links); j++ {
+				if (links[i].src-links[j].src)*(links[i].tr-links[j].tr) < 0 {
+					res++
+				}
+			}
+		}
+	}
+	return res
+}
+
+// copyLayers returns a deep copy of the layers
+func copyLayers(layers [][]int) [][]int {
+	res := make([][]int, len(layers))
+	for i, ly := range layers {
+		res[i] = append([]int(nil), ly...)
+	}
+	return res
+}
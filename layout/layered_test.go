@@ -0,0 +1,93 @@
+package layout_test
+
+import (
+	"testing"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/layout"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var opt = layout.Options{
+	NodeWidth:    100,
+	NodeHeight:   40,
+	NodeSpacing:  20,
+	LayerSpacing: 60,
+}
+
+func TestLayered(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		l := layout.Layered(graph.New(), opt)
+		assert.Equal(t, 0, l.Width)
+		assert.Equal(t, 0, l.Height)
+	})
+	t.Run("Success", func(t *testing.T) {
+		g := graph.New()
+		n1 := &graph.Node{ID: "1", Canonical: "im_out.tcp/443->443"}
+		n2 := &graph.Node{ID: "2", Canonical: "aws_lb.front"}
+		n3 := &graph.Node{ID: "3", Canonical: "aws_instance.front"}
+		n4 := &graph.Node{ID: "4", Canonical: "aws_db_instance.front"}
+		e1 := &graph.Edge{ID: "1", Source: n1.ID, Target: n2.ID}
+		e2 := &graph.Edge{ID: "2", Source: n2.ID, Target: n3.ID}
+		e3 := &graph.Edge{ID: "3", Source: n3.ID, Target: n4.ID}
+		e4 := &graph.Edge{ID: "4", Source: n2.ID, Target: n4.ID}
+
+		for _, n := range []*graph.Node{n4, n3, n2, n1} {
+			require.NoError(t, g.AddNode(n))
+		}
+		for _, e := range []*graph.Edge{e1, e2, e3, e4} {
+			require.NoError(t, g.AddEdge(e))
+		}
+
+		l := layout.Layered(g, opt)
+
+		// Each Node is on a different layer following
+		// the direction of the edges
+		assert.Equal(t, []int{110, 20}, n1.Position)
+		assert.Equal(t, []int{110, 120}, n2.Position)
+		assert.Equal(t, 220, n3.Position[1])
+		assert.Equal(t, 320, n4.Position[1])
+
+		// The e4 goes through 2 layers so it has a dummy
+		// point in the middle to not cross the n3
+		require.Len(t, l.Edges[e4.ID], 3)
+		assert.Equal(t, n2.Position, l.Edges[e4.ID][0])
+		assert.Equal(t, n4.Position, l.Edges[e4.ID][2])
+		assert.NotEqual(t, n3.Position, l.Edges[e4.ID][1])
+
+		assert.Equal(t, 220, l.Width)
+		assert.Equal(t, 340, l.Height)
+	})
+	t.Run("SuccessCyclic", func(t *testing.T) {
+		g := graph.New()
+		n1 := &graph.Node{ID: "1", Canonical: "aws_instance.a"}
+		n2 := &graph.Node{ID: "2", Canonical: "aws_instance.b"}
+		n3 := &graph.Node{ID: "3", Canonical: "aws_instance.c"}
+		e1 := &graph.Edge{ID: "1", Source: n1.ID, Target: n2.ID}
+		e2 := &graph.Edge{ID: "2", Source: n2.ID, Target: n3.ID}
+		e3 := &graph.Edge{ID: "3", Source: n3.ID, Target: n1.ID}
+		e4 := &graph.Edge{ID: "4", Source: n3.ID, Target: n3.ID}
+
+		for _, n := range []*graph.Node{n1, n2, n3} {
+			require.NoError(t, g.AddNode(n))
+		}
+		for _, e := range []*graph.Edge{e1, e2, e3, e4} {
+			require.NoError(t, g.AddEdge(e))
+		}
+
+		l := layout.Layered(g, opt)
+
+		assert.Equal(t, 20, n1.Position[1])
+		assert.Equal(t, 120, n2.Position[1])
+		assert.Equal(t, 220, n3.Position[1])
+
+		// The reversed edge still goes from
+		// the Source to the Target
+		points := l.Edges[e3.ID]
+		assert.Equal(t, n3.Position, points[0])
+		assert.Equal(t, n1.Position, points[len(points)-1])
+
+		assert.Equal(t, [][]int{n3.Position, n3.Position}, l.Edges[e4.ID])
+	})
+}
@@ -0,0 +1,18 @@
+package layout
+
+// Options are the possible options
+// that can be used to layout a Graph
+type Options struct {
+	// NodeWidth and NodeHeight are the size
+	// that each Node will take on the layout
+	NodeWidth  int
+	NodeHeight int
+
+	// NodeSpacing is the horizontal space between
+	// the Nodes on the same layer
+	NodeSpacing int
+
+	// LayerSpacing is the vertical space between
+	// each layer
+	LayerSpacing int
+}
@@ -0,0 +1,191 @@
+package ascii
+
+import "strings"
+
+// Directions in which a line of a cell goes,
+// they are combined to know which character
+// has to be used
+const (
+	up = 1 << iota
+	down
+	left
+	right
+)
+
+// charset holds the characters used to draw
+type charset struct {
+	// lines holds the direction mask -> character
+	lines map[int]rune
+
+	// node and edge hold the corners of the boxes on order:
+	// top-left, top-right, bottom-left, bottom-right
+	node [4]rune
+	edge [4]rune
+
+	horizontal rune
+	vertical   rune
+
+	arrowDown rune
+	arrowUp   rune
+
+	// teeDown and teeUp are used on the border
+	// of the boxes in which a line starts
+	teeDown rune
+	teeUp   rune
+}
+
+var (
+	unicodeCharset = charset{
+		lines: map[int]rune{
+			up: '│', down: '│', up | down: '│',
+			left: '─', right: '─', left | right: '─',
+			down | right: '┌', down | left: '┐', up | right: '└', up | left: '┘',
+			up | down | right: '├', up | down | left: '┤',
+			down | left | right: '┬', up | left | right: '┴',
+			up | down | left | right: '┼',
+		},
+		node:       [4]rune{'╭', '╮', '╰', '╯'},
+		edge:       [4]rune{'┌', '┐', '└', '┘'},
+		horizontal: '─',
+		vertical:   '│',
+		arrowDown:  '▼',
+		arrowUp:    '▲',
+		teeDown:    '┬',
+		teeUp:      '┴',
+	}
+
+	asciiCharset = charset{
+		lines: map[int]rune{
+			up: '|', down: '|', up | down: '|',
+			left: '-', right: '-', left | right: '-',
+		},
+		node:       [4]rune{'.', '.', '\'', '\''},
+		edge:       [4]rune{'+', '+', '+', '+'},
+		horizontal: '-',
+		vertical:   '|',
+		arrowDown:  'v',
+		arrowUp:    '^',
+		teeDown:    '+',
+		teeUp:      '+',
+	}
+)
+
+// canvas is a grid of characters in which
+// the lines are stored as masks of directions
+// until it's rendered
+type canvas struct {
+	cs    charset
+	runes [][]rune
+	masks [][]int
+}
+
+func newCanvas(cs charset, width, height int) *canvas {
+	c := &canvas{
+		cs:    cs,
+		runes: make([][]rune, height),
+		masks: make([][]int, height),
+	}
+	for i := range c.runes {
+		c.runes[i] = []rune(strings.Repeat(" ", width))
+		c.masks[i] = make([]int, width)
+	}
+	return c
+}
+
+// set sets the r on the x, y
+func (c *canvas) set(x, y int, r rune) {
+	if y < 0 || y >= len(c.runes) || x < 0 || x >= len(c.runes[y]) {
+		return
+	}
+	c.runes[y][x] = r
+}
+
+// mark adds the direction mask m to the x, y
+func (c *canvas) mark(x, y, m int) {
+	if y < 0 || y >= len(c.masks) || x < 0 || x >= len(c.masks[y]) {
+		return
+	}
+	c.masks[y][x] |= m
+}
+
+// vline draws a vertical line on x from y1 to y2
+func (c *canvas) vline(x, y1, y2 int) {
+	if y1 > y2 {
+		y1, y2 = y2, y1
+	}
+	for y := y1; y <= y2; y++ {
+		if y != y1 {
+			c.mark(x, y, up)
+		}
+		if y != y2 {
+			c.mark(x, y, down)
+		}
+		if y1 == y2 {
+			c.mark(x, y, up|down)
+		}
+	}
+}
+
+// hline draws a horizontal line on y from x1 to x2
+func (c *canvas) hline(y, x1, x2 int) {
+	if x1 > x2 {
+		x1, x2 = x2, x1
+	}
+	for x := x1; x <= x2; x++ {
+		if x != x1 {
+			c.mark(x, y, left)
+		}
+		if x != x2 {
+			c.mark(x, y, right)
+		}
+	}
+}
+
+// box draws a box with the label in it, the x, y are the
+// top left corner and w the total width of the box
+func (c *canvas) box(x, y, w int, label string, isEdge bool) {
+	corners := c.cs.node
+	if isEdge {
+		corners = c.cs.edge
+	}
+
+	c.set(x, y, corners[0])
+	c.set(x+w-1, y, corners[1])
+	c.set(x, y+2, corners[2])
+	c.set(x+w-1, y+2, corners[3])
+	for i := x + 1; i < x+w-1; i++ {
+		c.set(i, y, c.cs.horizontal)
+		c.set(i, y+2, c.cs.horizontal)
+	}
+	c.set(x, y+1, c.cs.vertical)
+	c.set(x+w-1, y+1, c.cs.vertical)
+
+	// The label is centered
+	lr := []rune(label)
+	start := x + (w-len(lr))/2
+	for i, r := range lr {
+		c.set(start+i, y+1, r)
+	}
+}
+
+// lines returns all the lines of the canvas rendered
+// and without the trailing spaces
+func (c *canvas) lines() []string {
+	res := make([]string, 0, len(c.runes))
+	for y, row := range c.runes {
+		for x, m := range c.masks[y] {
+			if m == 0 || row[x] != ' ' {
+				continue
+			}
+			r, ok := c.cs.lines[m]
+			if !ok {
+				// For the ASCII all the
+				// junctions are the same
+				r = '+'
+			}
+			row[x] = r
+		}
+		res = append(res, strings.TrimRight(string(row), " "))
+	}
+	return res
+}
@@ -0,0 +1,317 @@
+package ascii
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/layout"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/provider"
+	"github.com/cycloidio/inframap/provider/factory"
+)
+
+const (
+	// defaultWidth is the Width used if none
+	// is defined on the printer.Options
+	defaultWidth = 120
+
+	// boxHeight is the number of lines of a Node
+	boxHeight = 3
+
+	// nodeSpacing is the space between 2 Nodes
+	nodeSpacing = 2
+)
+
+// ASCII is the struct that implements the Printer
+// of box-and-arrow art to display it on the terminal
+type ASCII struct{}
+
+// segment is the part of an Edge that
+// goes from one layer to the next one
+type segment struct {
+	// layer is the upper layer of the segment
+	layer int
+
+	// top and bottom are the X of the
+	// vertices of the segment
+	top, bottom int
+
+	// down is the direction of the Edge
+	down bool
+
+	// topIsNode and bottomIsNode mean that the vertex
+	// is a graph.Node and not a point of the Edge
+	topIsNode, bottomIsNode bool
+
+	track int
+}
+
+// span returns the lowest and highest X of s
+func (s *segment) span() (int, int) {
+	if s.top < s.bottom {
+		return s.top, s.bottom
+	}
+	return s.bottom, s.top
+}
+
+// Print prints into w the g as box-and-arrow art. Each group
+// of connected Nodes is printed separately and if the result is
+// wider than the printer.Options.Width it's split in pages
+func (a ASCII) Print(g *graph.Graph, opt printer.Options, w io.Writer) error {
+	labels, err := printer.Labels(g, opt)
+	if err != nil {
+		return err
+	}
+
+	cs := unicodeCharset
+	if opt.PlainASCII {
+		cs = asciiCharset
+	}
+
+	width := opt.Width
+	if width <= 0 {
+		width = defaultWidth
+	}
+
+	lines := make([]string, 0)
+	for i, cg := range components(g) {
+		if i != 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, draw(cg, labels, cs)...)
+	}
+
+	_, err = io.WriteString(w, strings.Join(paginate(lines, width), "\n")+"\n")
+
+	return err
+}
+
+// draw returns the lines of g drawn with cs, the labels
+// are graph.Node.ID -> label
+func draw(g *graph.Graph, labels map[string]string, cs charset) []string {
+	var bw int
+	for _, n := range g.Nodes {
+		if l := utf8.RuneCountInString(labels[n.ID]) + 4; l > bw {
+			bw = l
+		}
+	}
+
+	// We only use the layout to get the layer (Y) and
+	// the order (X) of the Nodes, the height of each layer
+	// is calculated after depending on the number of Edges
+	l := layout.Layered(g, layout.Options{
+		NodeWidth:   bw,
+		NodeHeight:  1,
+		NodeSpacing: nodeSpacing,
+	})
+
+	var nlayers int
+	for _, n := range g.Nodes {
+		if n.Position[1]+1 > nlayers {
+			nlayers = n.Position[1] + 1
+		}
+	}
+
+	segments := make([]*segment, 0)
+	// dummies holds the [x, layer] of all the points
+	// in which the Edges go through a layer
+	dummies := make([][]int, 0)
+	for _, e := range g.Edges {
+		points := l.Edges[e.ID]
+		if e.Source == e.Target || len(points) < 2 {
+			continue
+		}
+		for i := 0; i < len(points)-1; i++ {
+			p, q := points[i], points[i+1]
+			s := &segment{
+				layer:        p[1],
+				top:          p[0],
+				bottom:       q[0],
+				down:         true,
+				topIsNode:    i == 0,
+				bottomIsNode: i+1 == len(points)-1,
+			}
+			if q[1] < p[1] {
+				s = &segment{
+					layer:        q[1],
+					top:          q[0],
+					bottom:       p[0],
+					topIsNode:    i+1 == len(points)-1,
+					bottomIsNode: i == 0,
+				}
+			}
+			segments = append(segments, s)
+			if i != 0 {
+				dummies = append(dummies, p)
+			}
+		}
+	}
+
+	// Each segment that is not straight needs
+	// a track (line) on the gap between the 2
+	// layers, the tracks are reused when the
+	// segments do not overlap
+	tracks := make([]int, nlayers)
+	sort.SliceStable(segments, func(i, j int) bool {
+		li, _ := segments[i].span()
+		lj, _ := segments[j].span()
+		return li < lj
+	})
+	ends := make([][]int, nlayers)
+	for _, s := range segments {
+		if s.top == s.bottom {
+			continue
+		}
+		lo, hi := s.span()
+		s.track = -1
+		for t, end := range ends[s.layer] {
+			if end < lo-1 {
+				s.track = t
+				ends[s.layer][t] = hi
+				break
+			}
+		}
+		if s.track == -1 {
+			s.track = len(ends[s.layer])
+			ends[s.layer] = append(ends[s.layer], hi)
+		}
+		tracks[s.layer] = len(ends[s.layer])
+	}
+
+	// layerTop holds the first line of each layer, between
+	// layers there is a line to start the Edges, the tracks and
+	// a line for the arrows
+	layerTop := make([]int, nlayers)
+	for i := 1; i < nlayers; i++ {
+		layerTop[i] = layerTop[i-1] + boxHeight + tracks[i-1] + 2
+	}
+
+	c := newCanvas(cs, l.Width, layerTop[nlayers-1]+boxHeight)
+
+	for _, n := range g.Nodes {
+		// If it's nil the pv, it means we do not know it so we'll use
+		// the RawProvider.
+		pv, rs, _ := factory.GetProviderAndResource(n.Canonical)
+		if pv == nil {
+			pv = provider.RawProvider{}
+		}
+		c.box(n.Position[0]-bw/2, layerTop[n.Position[1]], bw, labels[n.ID], pv.IsEdge(rs))
+	}
+
+	for _, d := range dummies {
+		c.vline(d[0], layerTop[d[1]], layerTop[d[1]]+boxHeight-1)
+	}
+
+	for _, s := range segments {
+		top := layerTop[s.layer] + boxHeight - 1
+		bottom := layerTop[s.layer+1]
+		if s.top == s.bottom {
+			c.vline(s.top, top, bottom)
+		} else {
+			track := top + 2 + s.track
+			c.vline(s.top, top, track)
+			c.hline(track, s.top, s.bottom)
+			c.vline(s.bottom, track, bottom)
+		}
+
+		if s.down {
+			if s.topIsNode {
+				c.set(s.top, top, cs.teeDown)
+			}
+			if s.bottomIsNode {
+				c.set(s.bottom, bottom-1, cs.arrowDown)
+			}
+		} else {
+			if s.bottomIsNode {
+				c.set(s.bottom, bottom, cs.teeUp)
+			}
+			if s.topIsNode {
+				c.set(s.top, top+1, cs.arrowUp)
+			}
+		}
+	}
+
+	return c.lines()
+}
+
+// components splits g into the groups of
+// Nodes that are connected between them
+func components(g *graph.Graph) []*graph.Graph {
+	parents := make(map[string]string, len(g.Nodes))
+	var find func(id string) string
+	find = func(id string) string {
+		if parents[id] == id {
+			return id
+		}
+		parents[id] = find(parents[id])
+		return parents[id]
+	}
+
+	for _, n := range g.Nodes {
+		parents[n.ID] = n.ID
+	}
+	for _, e := range g.Edges {
+		parents[find(e.Source)] = find(e.Target)
+	}
+
+	// The order of the components is the
+	// order of the first Node of each
+	res := make([]*graph.Graph, 0)
+	comps := make(map[string]*graph.Graph)
+	for _, n := range g.Nodes {
+		r := find(n.ID)
+		cg, ok := comps[r]
+		if !ok {
+			cg = graph.New()
+			comps[r] = cg
+			res = append(res, cg)
+		}
+		// The Nodes and Edges are already validated
+		// on g so the errors can be ignored
+		cg.AddNode(n)
+	}
+	for _, e := range g.Edges {
+		comps[find(e.Source)].AddEdge(e)
+	}
+
+	return res
+}
+
+// paginate splits the lines on pages of
+// width columns if they are wider
+func paginate(lines []string, width int) []string {
+	var max int
+	for _, l := range lines {
+		if c := utf8.RuneCountInString(l); c > max {
+			max = c
+		}
+	}
+
+	if max <= width {
+		return lines
+	}
+
+	npages := (max + width - 1) / width
+	res := make([]string, 0, (len(lines)+1)*npages)
+	for p := 0; p < npages; p++ {
+		res = append(res, fmt.Sprintf("-- page %d/%d (columns %d-%d) --", p+1, npages, p*width+1, (p+1)*width))
+		for _, l := range lines {
+			rl := []rune(l)
+			if len(rl) <= p*width {
+				res = append(res, "")
+				continue
+			}
+			end := (p + 1) * width
+			if end > len(rl) {
+				end = len(rl)
+			}
+			res = append(res, strings.TrimRight(string(rl[p*width:end]), " "))
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,111 @@
+package ascii_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/printer/ascii"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newGraph(t *testing.T) *graph.Graph {
+	g := graph.New()
+	n1 := &graph.Node{ID: "1", Canonical: "aws_lb.front"}
+	n2 := &graph.Node{ID: "2", Canonical: "aws_security_group.a"}
+	n3 := &graph.Node{ID: "3", Canonical: "aws_instance.b"}
+	n4 := &graph.Node{ID: "4", Canonical: "aws_s3_bucket.c"}
+
+	require.NoError(t, g.AddNode(n1))
+	require.NoError(t, g.AddNode(n2))
+	require.NoError(t, g.AddNode(n3))
+	require.NoError(t, g.AddNode(n4))
+	require.NoError(t, g.AddEdge(&graph.Edge{ID: "1", Source: n1.ID, Target: n2.ID}))
+	require.NoError(t, g.AddEdge(&graph.Edge{ID: "2", Source: n1.ID, Target: n3.ID}))
+
+	return g
+}
+
+func TestPrint(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		var buff bytes.Buffer
+		err := ascii.ASCII{}.Print(newGraph(t), printer.Options{}, &buff)
+		require.NoError(t, err)
+
+		assert.Equal(t, `             ╭──────────────────────╮
+             │     aws_lb.front     │
+             ╰───────────┬──────────╯
+                         │
+            ┌────────────┤
+            │            └────────────┐
+            ▼                         ▼
+┌──────────────────────┐  ╭──────────────────────╮
+│ aws_security_group.a │  │    aws_instance.b    │
+└──────────────────────┘  ╰──────────────────────╯
+
+╭─────────────────╮
+│ aws_s3_bucket.c │
+╰─────────────────╯
+`, buff.String())
+	})
+	t.Run("SuccessPlainASCII", func(t *testing.T) {
+		var buff bytes.Buffer
+		err := ascii.ASCII{}.Print(newGraph(t), printer.Options{PlainASCII: true}, &buff)
+		require.NoError(t, err)
+
+		assert.Equal(t, `             .----------------------.
+             |     aws_lb.front     |
+             '-----------+----------'
+                         |
+            +------------+
+            |            +------------+
+            v                         v
++----------------------+  .----------------------.
+| aws_security_group.a |  |    aws_instance.b    |
++----------------------+  '----------------------'
+
+.-----------------.
+| aws_s3_bucket.c |
+'-----------------'
+`, buff.String())
+	})
+	t.Run("SuccessPages", func(t *testing.T) {
+		var buff bytes.Buffer
+		err := ascii.ASCII{}.Print(newGraph(t), printer.Options{PlainASCII: true, Width: 30}, &buff)
+		require.NoError(t, err)
+
+		assert.Equal(t, `-- page 1/2 (columns 1-30) --
+             .----------------
+             |     aws_lb.fron
+             '-----------+----
+                         |
+            +------------+
+            |            +----
+            v
++----------------------+  .---
+| aws_security_group.a |  |
++----------------------+  '---
+
+.-----------------.
+| aws_s3_bucket.c |
+'-----------------'
+-- page 2/2 (columns 31-60) --
+------.
+t     |
+------'
+
+
+--------+
+        v
+-------------------.
+ aws_instance.b    |
+-------------------'
+
+
+
+
+`, buff.String())
+	})
+}
@@ -0,0 +1,88 @@
+package cypher
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/provider"
+	"github.com/cycloidio/inframap/provider/factory"
+)
+
+// Cypher is the struct that implements
+// the Printer of Neo4j Cypher format
+type Cypher struct{}
+
+const (
+	// resourceLabel is the label that all the
+	// Nodes have and the one used to MERGE them
+	resourceLabel = "Resource"
+
+	// relationshipType is the type of the
+	// relationships created from the Edges
+	relationshipType = "CONNECTS_TO"
+)
+
+// Print prints into w the g as Cypher statements. All of them use MERGE
+// so they can be run more than once, the Nodes are keyed by the
+// printer.Options.StackName and the Canonical so different stacks
+// can be loaded on the same database
+func (c Cypher) Print(g *graph.Graph, opt printer.Options, w io.Writer) error {
+	var sb strings.Builder
+
+	stack := quote(opt.StackName)
+
+	// canonicals holds graph.Node.ID -> graph.Node.Canonical
+	canonicals := make(map[string]string, len(g.Nodes))
+	for _, n := range g.Nodes {
+		// If it's nil the pv, it means we do not know it so we'll use
+		// the RawProvider.
+		pv, _, _ := factory.GetProviderAndResource(n.Canonical)
+		if pv == nil {
+			pv = provider.RawProvider{}
+		}
+		_, rt, _ := graph.ParseCanonical(n.Canonical)
+
+		canonicals[n.ID] = n.Canonical
+
+		fmt.Fprintf(&sb, "MERGE (n:%s {stack: %s, canonical: %s})\n", resourceLabel, stack, quote(n.Canonical))
+		fmt.Fprintf(&sb, "SET n:%s:%s, n.provider = %s, n.resource_type = %s, n.tfid = %s;\n",
+			label(pv.Type().String()), label(rt), quote(pv.Type().String()), quote(rt), quote(n.TFID),
+		)
+	}
+
+	for _, e := range g.Edges {
+		src, tr, err := printer.EdgeIDs(e, canonicals)
+		if err != nil {
+			return err
+		}
+
+		cans := make([]string, 0, len(e.Canonicals))
+		for _, can := range e.Canonicals {
+			cans = append(cans, quote(can))
+		}
+
+		fmt.Fprintf(&sb, "MATCH (s:%s {stack: %s, canonical: %s}), (t:%s {stack: %s, canonical: %s})\n", resourceLabel, stack, quote(src), resourceLabel, stack, quote(tr))
+		fmt.Fprintf(&sb, "MERGE (s)-[r:%s]->(t)\n", relationshipType)
+		fmt.Fprintf(&sb, "SET r.canonicals = [%s];\n", strings.Join(cans, ", "))
+	}
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+// quoteReplacer escapes the s to be on a Cypher string literal
+var quoteReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+// quote returns the s as a Cypher string literal
+func quote(s string) string {
+	return fmt.Sprintf("\"%s\"", quoteReplacer.Replace(s))
+}
+
+// label returns the s escaped as a Cypher label
+func label(s string) string {
+	return fmt.Sprintf("`%s`", strings.ReplaceAll(s, "`", "``"))
+}
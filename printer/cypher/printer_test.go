@@ -0,0 +1,49 @@
+package cypher_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/printer/cypher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrint(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		g := graph.New()
+		n1 := &graph.Node{ID: "1", Canonical: "module.app.aws_lb.front", TFID: "lb-id"}
+		n2 := &graph.Node{ID: "2", Canonical: `potato_instance.back["a"]`, TFID: "i-id"}
+		e := &graph.Edge{ID: "3", Source: n1.ID, Target: n2.ID}
+		e.AddCanonicals("aws_security_group.front", "aws_security_group.back")
+
+		require.NoError(t, g.AddNode(n1))
+		require.NoError(t, g.AddNode(n2))
+		require.NoError(t, g.AddEdge(e))
+
+		var buff bytes.Buffer
+		err := cypher.Cypher{}.Print(g, printer.Options{StackName: "prod"}, &buff)
+		require.NoError(t, err)
+
+		assert.Equal(t, `MERGE (n:Resource {stack: "prod", canonical: "module.app.aws_lb.front"})
+SET n:`+"`aws`:`aws_lb`"+`, n.provider = "aws", n.resource_type = "aws_lb", n.tfid = "lb-id";
+MERGE (n:Resource {stack: "prod", canonical: "potato_instance.back[\"a\"]"})
+SET n:`+"`raw`:`potato_instance`"+`, n.provider = "raw", n.resource_type = "potato_instance", n.tfid = "i-id";
+MATCH (s:Resource {stack: "prod", canonical: "module.app.aws_lb.front"}), (t:Resource {stack: "prod", canonical: "potato_instance.back[\"a\"]"})
+MERGE (s)-[r:CONNECTS_TO]->(t)
+SET r.canonicals = ["aws_security_group.front", "aws_security_group.back"];
+`, buff.String())
+	})
+	t.Run("SuccessEscape", func(t *testing.T) {
+		g := graph.New()
+		require.NoError(t, g.AddNode(&graph.Node{ID: "1", Canonical: "aws_lb.front", TFID: "say \"hi\"\n\tC:\\"}))
+
+		var buff bytes.Buffer
+		err := cypher.Cypher{}.Print(g, printer.Options{StackName: "prod"}, &buff)
+		require.NoError(t, err)
+
+		assert.Contains(t, buff.String(), `n.tfid = "say \"hi\"\n\tC:\\";`+"\n")
+	})
+}
@@ -0,0 +1,133 @@
+package cytoscape
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/provider"
+	"github.com/cycloidio/inframap/provider/factory"
+)
+
+// Cytoscape is the struct that implements the
+// Printer of Cytoscape.js elements JSON format
+type Cytoscape struct{}
+
+type document struct {
+	Elements elements `json:"elements"`
+}
+
+type elements struct {
+	Nodes []node `json:"nodes"`
+	Edges []edge `json:"edges"`
+}
+
+type node struct {
+	Data nodeData `json:"data"`
+}
+
+// nodeData is used for the Nodes and for the module
+// groups, which only have the ID, Label and Parent
+type nodeData struct {
+	ID           string `json:"id"`
+	Label        string `json:"label"`
+	Provider     string `json:"provider,omitempty"`
+	ResourceType string `json:"resource_type,omitempty"`
+	Parent       string `json:"parent,omitempty"`
+}
+
+type edge struct {
+	Data edgeData `json:"data"`
+}
+
+type edgeData struct {
+	ID         string   `json:"id"`
+	Source     string   `json:"source"`
+	Target     string   `json:"target"`
+	Canonicals []string `json:"canonicals"`
+}
+
+// Print prints into w the g in Cytoscape.js elements JSON format.
+// Each module is a compound Node, with the module path as ID,
+// that is the parent of the Nodes and submodules in it
+func (c Cytoscape) Print(g *graph.Graph, opt printer.Options, w io.Writer) error {
+	labels, err := printer.Labels(g, opt)
+	if err != nil {
+		return err
+	}
+
+	doc := document{
+		Elements: elements{
+			Nodes: make([]node, 0, len(g.Nodes)),
+			Edges: make([]edge, 0, len(g.Edges)),
+		},
+	}
+
+	groups := make(map[string]struct{})
+	for _, n := range g.Nodes {
+		// If it's nil the pv, it means we do not know it so we'll use
+		// the RawProvider.
+		pv, _, _ := factory.GetProviderAndResource(n.Canonical)
+		if pv == nil {
+			pv = provider.RawProvider{}
+		}
+		module, rt, _ := graph.ParseCanonical(n.Canonical)
+
+		// Each module of the path is added as a group
+		// if it was not already
+		var parent string
+		if module != "" {
+			for _, name := range strings.Split(strings.TrimPrefix(module, "module."), ".module.") {
+				id := "module." + name
+				if parent != "" {
+					id = parent + "." + id
+				}
+				if _, ok := groups[id]; !ok {
+					groups[id] = struct{}{}
+					doc.Elements.Nodes = append(doc.Elements.Nodes, node{
+						Data: nodeData{
+							ID:     id,
+							Label:  name,
+							Parent: parent,
+						},
+					})
+				}
+				parent = id
+			}
+		}
+
+		doc.Elements.Nodes = append(doc.Elements.Nodes, node{
+			Data: nodeData{
+				ID:           n.ID,
+				Label:        labels[n.ID],
+				Provider:     pv.Type().String(),
+				ResourceType: rt,
+				Parent:       parent,
+			},
+		})
+	}
+
+	for _, e := range g.Edges {
+		// The canonicals are always an array
+		// even if there are none
+		cans := e.Canonicals
+		if cans == nil {
+			cans = []string{}
+		}
+		doc.Elements.Edges = append(doc.Elements.Edges, edge{
+			Data: edgeData{
+				ID:         e.ID,
+				Source:     e.Source,
+				Target:     e.Target,
+				Canonicals: cans,
+			},
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(doc)
+}
@@ -0,0 +1,50 @@
+package cytoscape_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/printer/cytoscape"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrint(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		g := graph.New()
+		n1 := &graph.Node{ID: "1", Canonical: "module.app.module.lb.aws_lb.front"}
+		n2 := &graph.Node{ID: "2", Canonical: "module.app.aws_instance.front"}
+		n3 := &graph.Node{ID: "3", Canonical: "potato_instance.back"}
+		e1 := &graph.Edge{ID: "4", Source: n1.ID, Target: n2.ID}
+		e1.AddCanonicals("module.app.aws_security_group.front")
+		e2 := &graph.Edge{ID: "5", Source: n2.ID, Target: n3.ID}
+
+		require.NoError(t, g.AddNode(n1))
+		require.NoError(t, g.AddNode(n2))
+		require.NoError(t, g.AddNode(n3))
+		require.NoError(t, g.AddEdge(e1))
+		require.NoError(t, g.AddEdge(e2))
+
+		var buff bytes.Buffer
+		err := cytoscape.Cytoscape{}.Print(g, printer.Options{}, &buff)
+		require.NoError(t, err)
+
+		assert.JSONEq(t, `{
+			"elements": {
+				"nodes": [
+					{ "data": { "id": "module.app", "label": "app" } },
+					{ "data": { "id": "module.app.module.lb", "label": "lb", "parent": "module.app" } },
+					{ "data": { "id": "1", "label": "module.app.module.lb.aws_lb.front", "provider": "aws", "resource_type": "aws_lb", "parent": "module.app.module.lb" } },
+					{ "data": { "id": "2", "label": "module.app.aws_instance.front", "provider": "aws", "resource_type": "aws_instance", "parent": "module.app" } },
+					{ "data": { "id": "3", "label": "potato_instance.back", "provider": "raw", "resource_type": "potato_instance" } }
+				],
+				"edges": [
+					{ "data": { "id": "4", "source": "1", "target": "2", "canonicals": ["module.app.aws_security_group.front"] } },
+					{ "data": { "id": "5", "source": "2", "target": "3", "canonicals": [] } }
+				]
+			}
+		}`, buff.String())
+	})
+}
@@ -0,0 +1,175 @@
+package d2
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cycloidio/inframap/assets"
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/provider"
+	"github.com/cycloidio/inframap/provider/factory"
+)
+
+// D2 is the struct that implements
+// the Printer of D2 format
+type D2 struct{}
+
+// container is a Terraform module which
+// holds the Nodes and the submodules
+type container struct {
+	id    string
+	label string
+
+	// key is the full D2 key of the container
+	// ex: 'm0.m1'
+	key string
+
+	containers []*container
+	byName     map[string]*container
+
+	nodes []string
+}
+
+// get returns the subcontainer with the name, creating
+// it if it does not exists yet
+func (c *container) get(name string, nextID func() string) *container {
+	if sc, ok := c.byName[name]; ok {
+		return sc
+	}
+
+	id := nextID()
+	key := id
+	if c.key != "" {
+		key = fmt.Sprintf("%s.%s", c.key, id)
+	}
+	sc := &container{
+		id:     id,
+		label:  name,
+		key:    key,
+		byName: make(map[string]*container),
+	}
+	c.byName[name] = sc
+	c.containers = append(c.containers, sc)
+
+	return sc
+}
+
+// write writes the content of the container c
+// and the subcontainers with the indent
+func (c *container) write(sb *strings.Builder, indent string) {
+	for _, n := range c.nodes {
+		for _, l := range strings.Split(n, "\n") {
+			fmt.Fprintf(sb, "%s%s\n", indent, l)
+		}
+	}
+
+	for _, sc := range c.containers {
+		fmt.Fprintf(sb, "%s%s: %s {\n", indent, sc.id, quote(sc.label))
+		sc.write(sb, indent+"  ")
+		fmt.Fprintf(sb, "%s}\n", indent)
+	}
+}
+
+// Print prints into w the g in D2 format, the Nodes inside
+// of modules are nested on containers named as the module
+func (d D2) Print(g *graph.Graph, opt printer.Options, w io.Writer) error {
+	labels, err := printer.Labels(g, opt)
+	if err != nil {
+		return err
+	}
+
+	var (
+		mi   int
+		root = &container{byName: make(map[string]*container)}
+	)
+	nextID := func() string {
+		id := fmt.Sprintf("m%d", mi)
+		mi++
+		return id
+	}
+
+	// nodeKeys holds graph.Node.ID -> D2 key
+	nodeKeys := make(map[string]string, len(g.Nodes))
+	for i, n := range g.Nodes {
+		// If it's nil the pv, it means we do not know it so we'll use
+		// the RawProvider.
+		pv, rs, _ := factory.GetProviderAndResource(n.Canonical)
+		if pv == nil {
+			pv = provider.RawProvider{}
+		}
+
+		module, _, _ := graph.ParseCanonical(n.Canonical)
+
+		c := root
+		label := labels[n.ID]
+		if module != "" {
+			// The module is 'module.NAME.module.NAME' and the
+			// NAME could have '.' if it has an instance key
+			for _, name := range strings.Split(strings.TrimPrefix(module, "module."), ".module.") {
+				c = c.get(name, nextID)
+			}
+			// The module is already shown by the
+			// container so it's removed from the
+			// default label
+			if opt.LabelTemplate == "" {
+				label = strings.TrimPrefix(label, module+".")
+			}
+		}
+
+		id := fmt.Sprintf("n%d", i)
+		if c.key != "" {
+			nodeKeys[n.ID] = fmt.Sprintf("%s.%s", c.key, id)
+		} else {
+			nodeKeys[n.ID] = id
+		}
+
+		shape := "oval"
+		if pv.IsEdge(rs) {
+			shape = "rectangle"
+		}
+
+		attrs := []string{}
+		if opt.ShowIcons && n.Resource.Icon != "" {
+			pathIcon, err := assets.CacheIcon(pv.Type().String(), n.Resource.Icon)
+			if err != nil {
+				return err
+			}
+
+			shape = "image"
+			attrs = append(attrs, fmt.Sprintf("icon: %s", quote(pathIcon)))
+		}
+		attrs = append(attrs, fmt.Sprintf("shape: %s", shape))
+
+		c.nodes = append(c.nodes, fmt.Sprintf("%s: %s {\n  %s\n}", id, quote(label), strings.Join(attrs, "\n  ")))
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("direction: down\n")
+
+	root.write(&sb, "")
+
+	for _, e := range g.Edges {
+		src, tr, err := printer.EdgeIDs(e, nodeKeys)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(&sb, "%s -> %s\n", src, tr)
+	}
+
+	_, err = io.WriteString(w, sb.String())
+
+	return err
+}
+
+// quoteReplacer escapes the s to be on a D2 double quoted string, the
+// '$' are escaped as they are used for the substitutions ('${x}')
+var quoteReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, `$`, `\$`)
+
+// quote returns the s as a D2 double quoted string
+func quote(s string) string {
+	return fmt.Sprintf("\"%s\"", quoteReplacer.Replace(s))
+}
@@ -0,0 +1,76 @@
+package d2_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/printer/d2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrint(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		g := graph.New()
+		nodes := []*graph.Node{
+			{ID: "1", Canonical: "im_out.tcp/443->443"},
+			{ID: "2", Canonical: "module.magento.aws_lb.front"},
+			{ID: "3", Canonical: "module.magento.aws_security_group.front"},
+			{ID: "4", Canonical: "module.magento.module.db.aws_db_instance.main"},
+			{ID: "5", Canonical: "module.magento.aws_instance.front"},
+		}
+		for _, n := range nodes {
+			require.NoError(t, g.AddNode(n))
+		}
+		require.NoError(t, g.AddEdge(&graph.Edge{ID: "1", Source: "1", Target: "2"}))
+		require.NoError(t, g.AddEdge(&graph.Edge{ID: "2", Source: "2", Target: "3"}))
+		require.NoError(t, g.AddEdge(&graph.Edge{ID: "3", Source: "3", Target: "4"}))
+		require.NoError(t, g.AddEdge(&graph.Edge{ID: "4", Source: "2", Target: "5"}))
+
+		var buff bytes.Buffer
+		err := d2.D2{}.Print(g, printer.Options{}, &buff)
+		require.NoError(t, err)
+
+		assert.Equal(t, `direction: down
+n0: "im_out.tcp/443->443" {
+  shape: oval
+}
+m0: "magento" {
+  n1: "aws_lb.front" {
+    shape: oval
+  }
+  n2: "aws_security_group.front" {
+    shape: rectangle
+  }
+  n4: "aws_instance.front" {
+    shape: oval
+  }
+  m1: "db" {
+    n3: "aws_db_instance.main" {
+      shape: oval
+    }
+  }
+}
+n0 -> m0.n1
+m0.n1 -> m0.n2
+m0.n2 -> m0.m1.n3
+m0.n1 -> m0.n4
+`, buff.String())
+	})
+	t.Run("SuccessEscape", func(t *testing.T) {
+		g := graph.New()
+		require.NoError(t, g.AddNode(&graph.Node{ID: "1", Canonical: "aws_lb.front", TFID: "say \"${hi}\"\nC:\\"}))
+
+		var buff bytes.Buffer
+		err := d2.D2{}.Print(g, printer.Options{LabelTemplate: "{{.TFID}}"}, &buff)
+		require.NoError(t, err)
+
+		assert.Equal(t, `direction: down
+n0: "say \"\${hi}\"\nC:\\" {
+  shape: oval
+}
+`, buff.String())
+	})
+}
@@ -4,20 +4,14 @@ import (
 	"bytes"
 	"fmt"
 	"io"
-	"os"
-	"path"
+	"strings"
 
-	"github.com/adrg/xdg"
 	"github.com/awalterschulze/gographviz"
+	"github.com/cycloidio/inframap/assets"
 	"github.com/cycloidio/inframap/graph"
 	"github.com/cycloidio/inframap/printer"
 	"github.com/cycloidio/inframap/provider"
 	"github.com/cycloidio/inframap/provider/factory"
-	"github.com/markbates/pkger"
-
-	// As we require to load the assets to be used
-	// we import it as empty
-	_ "github.com/cycloidio/inframap/assets"
 )
 
 // Dot is the struct that implements
@@ -26,12 +20,29 @@ type Dot struct{}
 
 // Print prints into w the g in DOT format
 func (d Dot) Print(g *graph.Graph, opt printer.Options, w io.Writer) error {
+	labels, err := printer.Labels(g, opt)
+	if err != nil {
+		return err
+	}
+
 	graph := gographviz.NewGraph()
 	parentName := "G"
 	graph.SetName(parentName)
 	graph.SetDir(true)
 	graph.SetStrict(true)
 
+	clusters, err := addClusters(graph, parentName, g)
+	if err != nil {
+		return err
+	}
+
+	used := newThemeUsed()
+	if opt.Theme != nil {
+		if err := applyGraphTheme(graph, parentName, opt.Theme); err != nil {
+			return err
+		}
+	}
+
 	for _, n := range g.Nodes {
 		// If it's nil the pv, it means we do not know it so we'll use
 		// the RawProvider.
@@ -50,45 +61,45 @@ func (d Dot) Print(g *graph.Graph, opt printer.Options, w io.Writer) error {
 		}
 
 		if opt.ShowIcons && n.Resource.Icon != "" {
-			ext := path.Ext(n.Resource.Icon)
-			pngIcon := fmt.Sprintf("%s.png", n.Resource.Icon[0:len(n.Resource.Icon)-len(ext)])
-			assetPath := path.Join("inframap", "assets", pv.Type().String(), pngIcon)
-			pathIcon := path.Join(xdg.CacheHome, assetPath)
+			pathIcon, err := assets.CacheIcon(pv.Type().String(), n.Resource.Icon)
+			if err != nil {
+				return err
+			}
 
 			attr["image"] = fmt.Sprintf("%q", pathIcon)
 			attr["shape"] = "plaintext"
 			attr["labelloc"] = "b"
 			attr["height"] = "1.15"
 			attr["imagepos"] = "tc"
+		}
 
-			// If the file does not exists on the Cache path, we have to write it,
-			// if not it means it's already correct so nothing to be done
-			if _, err := os.Stat(pathIcon); os.IsNotExist(err) {
-				p, err := xdg.CacheFile(assetPath)
-				if err != nil {
-					return err
-				}
-
-				f, err := os.Create(p)
-				if err != nil {
-					return err
-				}
-
-				iconFile, err := pkger.Open(path.Join("/assets", "icons", pv.Type().String(), pngIcon))
-				if err != nil {
-					return err
-				}
-
-				if _, err = io.Copy(f, iconFile); err != nil {
-					return err
-				}
-
-				f.Close()
-				iconFile.Close()
+		if opt.Theme != nil {
+			applyNodeStyle(attr, opt.Theme.NodeStyle(pv.Type().String(), rs), opt.Theme.Font)
+			if _, ok := opt.Theme.Providers[pv.Type().String()]; ok {
+				used.providers[pv.Type().String()] = struct{}{}
+			}
+			if _, ok := opt.Theme.Resources[rs]; ok {
+				used.resources[rs] = struct{}{}
 			}
 		}
 
-		graph.AddNode(parentName, fmt.Sprintf("%q", n.Canonical), attr)
+		// The Nodes of a plan are styled by the action
+		// that will be done to them, over the Theme
+		if n.Action != "" {
+			applyNodeStyle(attr, actionStyle(opt.Theme, n.Action), "")
+			used.actions[n.Action] = struct{}{}
+		}
+
+		parent := parentName
+		if c, ok := clusters[n.Group]; ok {
+			parent = c
+		}
+
+		if labels[n.ID] != n.Canonical {
+			attr["label"] = fmt.Sprintf("%q", labels[n.ID])
+		}
+
+		graph.AddNode(parent, fmt.Sprintf("%q", n.Canonical), attr)
 	}
 
 	for _, e := range g.Edges {
@@ -102,7 +113,39 @@ func (d Dot) Print(g *graph.Graph, opt printer.Options, w io.Writer) error {
 			return err
 		}
 
-		graph.AddEdge(fmt.Sprintf("%q", src.Canonical), fmt.Sprintf("%q", tr.Canonical), true, nil)
+		attr := make(map[string]string)
+		if len(e.Ports) != 0 {
+			labels := make([]string, 0, len(e.Ports))
+			for _, p := range e.Ports {
+				labels = append(labels, p.String())
+			}
+			attr["label"] = fmt.Sprintf("%q", strings.Join(labels, ", "))
+		}
+
+		if opt.Theme != nil {
+			spv, _, _ := factory.GetProviderAndResource(src.Canonical)
+			if spv == nil {
+				spv = provider.RawProvider{}
+			}
+			tpv, _, _ := factory.GetProviderAndResource(tr.Canonical)
+			if tpv == nil {
+				tpv = provider.RawProvider{}
+			}
+
+			c := edgeCategory(e, spv, tpv)
+			applyEdgeStyle(attr, opt.Theme.Edges[c], opt.Theme.Font)
+			if _, ok := opt.Theme.Edges[c]; ok {
+				used.edges[c] = struct{}{}
+			}
+		}
+
+		graph.AddEdge(fmt.Sprintf("%q", src.Canonical), fmt.Sprintf("%q", tr.Canonical), true, attr)
+	}
+
+	if opt.Theme != nil && opt.Theme.Legend {
+		if err := addLegend(graph, parentName, opt.Theme, used); err != nil {
+			return err
+		}
 	}
 
 	buff := bytes.NewBufferString(graph.String())
@@ -110,3 +153,54 @@ func (d Dot) Print(g *graph.Graph, opt printer.Options, w io.Writer) error {
 
 	return nil
 }
+
+// addClusters adds to gv a 'subgraph cluster_*' for each graph.Group of g
+// that has Nodes, directly or on the subgroups, nested as the Groups are.
+// It returns the graph.Group.ID -> cluster name
+func addClusters(gv *gographviz.Graph, parentName string, g *graph.Graph) (map[string]string, error) {
+	// used holds the IDs of the Groups that
+	// have Nodes on them or on subgroups
+	used := make(map[string]struct{})
+	for _, n := range g.Nodes {
+		gID := n.Group
+		for gID != "" {
+			if _, ok := used[gID]; ok {
+				break
+			}
+			used[gID] = struct{}{}
+
+			gr, err := g.GetGroupByID(gID)
+			if err != nil {
+				return nil, err
+			}
+			gID = gr.Parent
+		}
+	}
+
+	// As the Parent is always added before
+	// the child the order of the g.Groups
+	// is the one we need
+	clusters := make(map[string]string)
+	for i, gr := range g.Groups {
+		if _, ok := used[gr.ID]; !ok {
+			continue
+		}
+
+		parent := parentName
+		if gr.Parent != "" {
+			parent = clusters[gr.Parent]
+		}
+
+		name := fmt.Sprintf("cluster_%d", i)
+		err := gv.AddSubGraph(parent, name, map[string]string{
+			"label": fmt.Sprintf("%q", fmt.Sprintf("%s (%s)", gr.Name, gr.Type)),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		clusters[gr.ID] = name
+	}
+
+	return clusters, nil
+}
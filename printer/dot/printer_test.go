@@ -0,0 +1,187 @@
+package dot_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/printer/dot"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrint(t *testing.T) {
+	t.Run("SuccessWithGroups", func(t *testing.T) {
+		g := graph.New()
+		require.NoError(t, g.AddGroup(&graph.Group{ID: "aws_vpc.vpc", Name: "vpc", Type: "aws_vpc"}))
+		require.NoError(t, g.AddGroup(&graph.Group{ID: "aws_vpc.vpc/aws_subnet.subnet", Name: "subnet", Type: "aws_subnet", Parent: "aws_vpc.vpc"}))
+		require.NoError(t, g.AddGroup(&graph.Group{ID: "aws_vpc.empty", Name: "empty", Type: "aws_vpc"}))
+
+		nodes := []*graph.Node{
+			{ID: "1", Canonical: "im_out.tcp/443->443"},
+			{ID: "2", Canonical: "aws_lb.front", Group: "aws_vpc.vpc"},
+			{ID: "3", Canonical: "aws_instance.front", Group: "aws_vpc.vpc/aws_subnet.subnet"},
+		}
+		for _, n := range nodes {
+			require.NoError(t, g.AddNode(n))
+		}
+		require.NoError(t, g.AddEdge(&graph.Edge{ID: "1", Source: "1", Target: "2"}))
+		require.NoError(t, g.AddEdge(&graph.Edge{ID: "2", Source: "2", Target: "3"}))
+
+		var buff bytes.Buffer
+		err := dot.Dot{}.Print(g, printer.Options{}, &buff)
+		require.NoError(t, err)
+
+		assert.Equal(t, `strict digraph G {
+	"im_out.tcp/443->443"->"aws_lb.front";
+	"aws_lb.front"->"aws_instance.front";
+	subgraph cluster_0 {
+	label="vpc (aws_vpc)";
+	"aws_lb.front" [ shape=ellipse ];
+	subgraph cluster_1 {
+	label="subnet (aws_subnet)";
+	"aws_instance.front" [ shape=ellipse ];
+
+}
+;
+
+}
+;
+	"im_out.tcp/443->443" [ shape=ellipse ];
+
+}
+`, buff.String())
+	})
+	t.Run("SuccessWithPorts", func(t *testing.T) {
+		g := graph.New()
+		nodes := []*graph.Node{
+			{ID: "1", Canonical: "aws_lb.front"},
+			{ID: "2", Canonical: "aws_instance.front"},
+		}
+		for _, n := range nodes {
+			require.NoError(t, g.AddNode(n))
+		}
+		e := &graph.Edge{ID: "1", Source: "1", Target: "2"}
+		e.AddPorts(graph.Port{Protocol: "tcp", FromPort: 443, ToPort: 443}, graph.Port{Protocol: "tcp", FromPort: 80, ToPort: 80})
+		require.NoError(t, g.AddEdge(e))
+
+		var buff bytes.Buffer
+		err := dot.Dot{}.Print(g, printer.Options{}, &buff)
+		require.NoError(t, err)
+
+		assert.Equal(t, `strict digraph G {
+	"aws_lb.front"->"aws_instance.front"[ label="tcp/443, tcp/80" ];
+	"aws_instance.front" [ shape=ellipse ];
+	"aws_lb.front" [ shape=ellipse ];
+
+}
+`, buff.String())
+	})
+	t.Run("SuccessWithTheme", func(t *testing.T) {
+		g := graph.New()
+		nodes := []*graph.Node{
+			{ID: "1", Canonical: "im_out.tcp/443->443"},
+			{ID: "2", Canonical: "aws_lb.front"},
+			{ID: "3", Canonical: "aws_instance.front"},
+		}
+		for _, n := range nodes {
+			require.NoError(t, g.AddNode(n))
+		}
+		require.NoError(t, g.AddEdge(&graph.Edge{ID: "1", Source: "1", Target: "2"}))
+		require.NoError(t, g.AddEdge(&graph.Edge{ID: "2", Source: "2", Target: "3", Canonicals: []string{"aws_security_group.front"}}))
+
+		theme := &printer.Theme{
+			RankDir:   "LR",
+			Providers: map[string]printer.NodeStyle{"aws": {FillColor: "#ff9900"}},
+			Resources: map[string]printer.NodeStyle{"aws_lb": {Shape: "box"}, "aws_s3_bucket": {Shape: "cylinder"}},
+			Edges: map[string]printer.EdgeStyle{
+				printer.EdgeExternal:   {Style: "dashed"},
+				printer.EdgeConnection: {Color: "red", PenWidth: 2},
+			},
+			Legend: true,
+		}
+
+		var buff bytes.Buffer
+		err := dot.Dot{}.Print(g, printer.Options{Theme: theme}, &buff)
+		require.NoError(t, err)
+
+		assert.Equal(t, `strict digraph G {
+	rankdir=LR;
+	"im_out.tcp/443->443"->"aws_lb.front"[ style=dashed ];
+	"aws_lb.front"->"aws_instance.front"[ color="red", penwidth=2 ];
+	"legend_edge_connection_src"->"legend_edge_connection_tr"[ color="red", label="connection", penwidth=2 ];
+	"legend_edge_external_src"->"legend_edge_external_tr"[ label="external", style=dashed ];
+	subgraph cluster_legend {
+	label="Legend";
+	"legend_edge_connection_src" [ shape=point ];
+	"legend_edge_connection_tr" [ shape=point ];
+	"legend_edge_external_src" [ shape=point ];
+	"legend_edge_external_tr" [ shape=point ];
+	"legend_provider_aws" [ fillcolor="#ff9900", label="aws", style=filled ];
+	"legend_resource_aws_lb" [ label="aws_lb", shape=box ];
+
+}
+;
+	"aws_instance.front" [ fillcolor="#ff9900", shape=ellipse, style=filled ];
+	"aws_lb.front" [ fillcolor="#ff9900", shape=box, style=filled ];
+	"im_out.tcp/443->443" [ shape=ellipse ];
+
+}
+`, buff.String())
+	})
+	t.Run("SuccessWithLabelTemplate", func(t *testing.T) {
+		g := graph.New()
+		nodes := []*graph.Node{
+			{ID: "1", Canonical: "module.app.aws_lb.front", Attributes: map[string]interface{}{"tags.Name": "front-lb"}},
+			{ID: "2", Canonical: "module.app.aws_instance.front"},
+		}
+		for _, n := range nodes {
+			require.NoError(t, g.AddNode(n))
+		}
+		require.NoError(t, g.AddEdge(&graph.Edge{ID: "1", Source: "1", Target: "2"}))
+
+		var buff bytes.Buffer
+		err := dot.Dot{}.Print(g, printer.Options{LabelTemplate: `{{.Attr "tags.Name" | default .Name}} ({{.Type}})`}, &buff)
+		require.NoError(t, err)
+
+		assert.Equal(t, `strict digraph G {
+	"module.app.aws_lb.front"->"module.app.aws_instance.front";
+	"module.app.aws_instance.front" [ label="front (aws_instance)", shape=ellipse ];
+	"module.app.aws_lb.front" [ label="front-lb (aws_lb)", shape=ellipse ];
+
+}
+`, buff.String())
+	})
+	t.Run("SuccessWithActions", func(t *testing.T) {
+		g := graph.New()
+		nodes := []*graph.Node{
+			{ID: "1", Canonical: "aws_lb.front", Action: graph.ActionUpdate},
+			{ID: "2", Canonical: "aws_instance.front", Action: graph.ActionCreate},
+			{ID: "3", Canonical: "aws_db_instance.front"},
+		}
+		for _, n := range nodes {
+			require.NoError(t, g.AddNode(n))
+		}
+		require.NoError(t, g.AddEdge(&graph.Edge{ID: "1", Source: "1", Target: "2"}))
+		require.NoError(t, g.AddEdge(&graph.Edge{ID: "2", Source: "2", Target: "3"}))
+
+		theme := &printer.Theme{
+			Actions: map[string]printer.NodeStyle{graph.ActionCreate: {FillColor: "green"}},
+		}
+
+		var buff bytes.Buffer
+		err := dot.Dot{}.Print(g, printer.Options{Theme: theme}, &buff)
+		require.NoError(t, err)
+
+		assert.Equal(t, `strict digraph G {
+	"aws_lb.front"->"aws_instance.front";
+	"aws_instance.front"->"aws_db_instance.front";
+	"aws_db_instance.front" [ shape=ellipse ];
+	"aws_instance.front" [ fillcolor="green", shape=ellipse, style=filled ];
+	"aws_lb.front" [ fillcolor="#fce8b2", shape=ellipse, style=filled ];
+
+}
+`, buff.String())
+	})
+}
@@ -0,0 +1,195 @@
+package dot
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/awalterschulze/gographviz"
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/provider"
+)
+
+// legendName is the name of the legend subgraph
+const legendName = "cluster_legend"
+
+// actionStyles are the default styles of the
+// Nodes with each graph.Node.Action
+var actionStyles = map[string]printer.NodeStyle{
+	graph.ActionCreate:  {FillColor: "#b7e1cd"},
+	graph.ActionUpdate:  {FillColor: "#fce8b2"},
+	graph.ActionDelete:  {FillColor: "#f4c7c3"},
+	graph.ActionReplace: {FillColor: "#ffd8a8"},
+}
+
+// themeUsed holds the keys of the printer.Theme
+// that have been used, so the legend only
+// shows the relevant ones
+type themeUsed struct {
+	providers map[string]struct{}
+	resources map[string]struct{}
+	edges     map[string]struct{}
+	actions   map[string]struct{}
+}
+
+func newThemeUsed() *themeUsed {
+	return &themeUsed{
+		providers: make(map[string]struct{}),
+		resources: make(map[string]struct{}),
+		edges:     make(map[string]struct{}),
+		actions:   make(map[string]struct{}),
+	}
+}
+
+// applyGraphTheme applies the general styles of t to the gv
+func applyGraphTheme(gv *gographviz.Graph, parentName string, t *printer.Theme) error {
+	if t.RankDir != "" {
+		if err := gv.AddAttr(parentName, "rankdir", t.RankDir); err != nil {
+			return err
+		}
+	}
+
+	if t.Font != "" {
+		if err := gv.AddAttr(parentName, "fontname", fmt.Sprintf("%q", t.Font)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyNodeStyle sets on the attr the non empty values of the ns
+func applyNodeStyle(attr map[string]string, ns printer.NodeStyle, font string) {
+	if ns.Shape != "" {
+		attr["shape"] = ns.Shape
+	}
+	if ns.Color != "" {
+		attr["color"] = fmt.Sprintf("%q", ns.Color)
+	}
+	if ns.FillColor != "" {
+		attr["style"] = "filled"
+		attr["fillcolor"] = fmt.Sprintf("%q", ns.FillColor)
+	}
+	if ns.FontColor != "" {
+		attr["fontcolor"] = fmt.Sprintf("%q", ns.FontColor)
+	}
+	if ns.Width != 0 {
+		attr["width"] = formatFloat(ns.Width)
+	}
+	if ns.Height != 0 {
+		attr["height"] = formatFloat(ns.Height)
+	}
+	if font != "" {
+		attr["fontname"] = fmt.Sprintf("%q", font)
+	}
+}
+
+// applyEdgeStyle sets on the attr the non empty values of the es
+func applyEdgeStyle(attr map[string]string, es printer.EdgeStyle, font string) {
+	if es.Style != "" {
+		attr["style"] = es.Style
+	}
+	if es.Color != "" {
+		attr["color"] = fmt.Sprintf("%q", es.Color)
+	}
+	if es.FontColor != "" {
+		attr["fontcolor"] = fmt.Sprintf("%q", es.FontColor)
+	}
+	if es.PenWidth != 0 {
+		attr["penwidth"] = formatFloat(es.PenWidth)
+	}
+	if font != "" {
+		attr["fontname"] = fmt.Sprintf("%q", font)
+	}
+}
+
+// actionStyle returns the style of the Nodes with the action a,
+// the default one with the one of the t, if any, over it
+func actionStyle(t *printer.Theme, a string) printer.NodeStyle {
+	ns := actionStyles[a]
+	if t != nil {
+		ns = ns.Merge(t.Actions[a])
+	}
+	return ns
+}
+
+// edgeCategory returns the category of the e that goes
+// from the src to the tr
+func edgeCategory(e *graph.Edge, src, tr provider.Provider) string {
+	if src.Type() == provider.IM || tr.Type() == provider.IM {
+		return printer.EdgeExternal
+	}
+
+	if len(e.Canonicals) != 0 {
+		return printer.EdgeConnection
+	}
+
+	return printer.EdgeDependency
+}
+
+// addLegend adds to gv a subgraph with one Node for each style
+// of the providers and resources used and one edge for
+// each style of the edges used
+func addLegend(gv *gographviz.Graph, parentName string, t *printer.Theme, used *themeUsed) error {
+	err := gv.AddSubGraph(parentName, legendName, map[string]string{
+		"label": fmt.Sprintf("%q", "Legend"),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, p := range sortedKeys(used.providers) {
+		attr := map[string]string{
+			"label": fmt.Sprintf("%q", p),
+		}
+		applyNodeStyle(attr, t.Node.Merge(t.Providers[p]), t.Font)
+		gv.AddNode(legendName, fmt.Sprintf("%q", "legend_provider_"+p), attr)
+	}
+
+	for _, r := range sortedKeys(used.resources) {
+		attr := map[string]string{
+			"label": fmt.Sprintf("%q", r),
+		}
+		applyNodeStyle(attr, t.Node.Merge(t.Resources[r]), t.Font)
+		gv.AddNode(legendName, fmt.Sprintf("%q", "legend_resource_"+r), attr)
+	}
+
+	for _, a := range sortedKeys(used.actions) {
+		attr := map[string]string{
+			"label": fmt.Sprintf("%q", a),
+		}
+		applyNodeStyle(attr, t.Node.Merge(actionStyle(t, a)), t.Font)
+		gv.AddNode(legendName, fmt.Sprintf("%q", "legend_action_"+a), attr)
+	}
+
+	// Each edge category is represented by
+	// an edge between 2 points
+	for _, c := range sortedKeys(used.edges) {
+		src := fmt.Sprintf("%q", "legend_edge_"+c+"_src")
+		tr := fmt.Sprintf("%q", "legend_edge_"+c+"_tr")
+		gv.AddNode(legendName, src, map[string]string{"shape": "point"})
+		gv.AddNode(legendName, tr, map[string]string{"shape": "point"})
+
+		attr := map[string]string{
+			"label": fmt.Sprintf("%q", c),
+		}
+		applyEdgeStyle(attr, t.Edges[c], t.Font)
+		gv.AddEdge(src, tr, true, attr)
+	}
+
+	return nil
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,202 @@
+package drawio
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/cycloidio/inframap/assets"
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/layout"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/provider"
+	"github.com/cycloidio/inframap/provider/factory"
+)
+
+const (
+	// iconSize is the width and height of the
+	// Nodes with icons
+	iconSize = 64
+
+	// shapeWidth and shapeHeight are the size
+	// of the Nodes without icons
+	shapeWidth  = 160
+	shapeHeight = 60
+
+	// nodeSpacing and layerSpacing are the space
+	// between the Nodes on the layout
+	nodeSpacing  = 40
+	layerSpacing = 80
+
+	nodeStyle     = "ellipse;whiteSpace=wrap;"
+	edgeNodeStyle = "rounded=0;whiteSpace=wrap;"
+	iconStyle     = "shape=image;verticalLabelPosition=bottom;verticalAlign=top;labelBackgroundColor=default;aspect=fixed;imageAspect=0;image=data:image/png,%s;"
+	edgeStyle     = "edgeStyle=orthogonalEdgeStyle;rounded=0;orthogonalLoop=1;jettySize=auto;endArrow=classic;"
+)
+
+// DrawIO is the struct that implements the Printer
+// of diagrams.net (draw.io) mxGraph XML format
+type DrawIO struct{}
+
+type mxFile struct {
+	XMLName xml.Name  `xml:"mxfile"`
+	Host    string    `xml:"host,attr"`
+	Diagram mxDiagram `xml:"diagram"`
+}
+
+type mxDiagram struct {
+	ID    string       `xml:"id,attr"`
+	Name  string       `xml:"name,attr"`
+	Model mxGraphModel `xml:"mxGraphModel"`
+}
+
+type mxGraphModel struct {
+	Grid     int      `xml:"grid,attr"`
+	GridSize int      `xml:"gridSize,attr"`
+	Arrows   int      `xml:"arrows,attr"`
+	Connect  int      `xml:"connect,attr"`
+	Cells    []mxCell `xml:"root>mxCell"`
+}
+
+type mxCell struct {
+	ID       string      `xml:"id,attr"`
+	Value    string      `xml:"value,attr,omitempty"`
+	Style    string      `xml:"style,attr,omitempty"`
+	Vertex   string      `xml:"vertex,attr,omitempty"`
+	Edge     string      `xml:"edge,attr,omitempty"`
+	Parent   string      `xml:"parent,attr,omitempty"`
+	Source   string      `xml:"source,attr,omitempty"`
+	Target   string      `xml:"target,attr,omitempty"`
+	Geometry *mxGeometry `xml:"mxGeometry,omitempty"`
+}
+
+type mxGeometry struct {
+	X        int    `xml:"x,attr,omitempty"`
+	Y        int    `xml:"y,attr,omitempty"`
+	Width    int    `xml:"width,attr,omitempty"`
+	Height   int    `xml:"height,attr,omitempty"`
+	Relative string `xml:"relative,attr,omitempty"`
+	As       string `xml:"as,attr"`
+}
+
+// Print prints into w the g in draw.io format
+func (d DrawIO) Print(g *graph.Graph, opt printer.Options, w io.Writer) error {
+	labels, err := printer.Labels(g, opt)
+	if err != nil {
+		return err
+	}
+
+	// The first 2 cells are the required root
+	// and default layer of the diagram
+	cells := []mxCell{
+		{ID: "0"},
+		{ID: "1", Parent: "0"},
+	}
+
+	// The labels of the Nodes with icons are on the
+	// bottom so we reserve some space for them
+	layout.Layered(g, layout.Options{
+		NodeWidth:    shapeWidth,
+		NodeHeight:   shapeHeight + iconSize,
+		NodeSpacing:  nodeSpacing,
+		LayerSpacing: layerSpacing,
+	})
+
+	// nodeIDs holds graph.Node.ID -> mxCell.ID
+	nodeIDs := make(map[string]string, len(g.Nodes))
+	for i, n := range g.Nodes {
+		// If it's nil the pv, it means we do not know it so we'll use
+		// the RawProvider.
+		pv, rs, _ := factory.GetProviderAndResource(n.Canonical)
+		if pv == nil {
+			pv = provider.RawProvider{}
+		}
+
+		id := fmt.Sprintf("n%d", i)
+		nodeIDs[n.ID] = id
+
+		// The Position is the center of the Node
+		geo := &mxGeometry{
+			X:      n.Position[0] - shapeWidth/2,
+			Y:      n.Position[1] - shapeHeight/2,
+			Width:  shapeWidth,
+			Height: shapeHeight,
+			As:     "geometry",
+		}
+
+		style := nodeStyle
+		if pv.IsEdge(rs) {
+			style = edgeNodeStyle
+		}
+
+		if opt.ShowIcons && n.Resource.Icon != "" {
+			icon, err := assets.IconBase64(pv.Type().String(), n.Resource.Icon)
+			if err != nil {
+				return err
+			}
+			style = fmt.Sprintf(iconStyle, icon)
+			geo.X = n.Position[0] - iconSize/2
+			geo.Y = n.Position[1] - iconSize/2
+			geo.Width = iconSize
+			geo.Height = iconSize
+		}
+
+		cells = append(cells, mxCell{
+			ID:       id,
+			Value:    labels[n.ID],
+			Style:    style,
+			Vertex:   "1",
+			Parent:   "1",
+			Geometry: geo,
+		})
+	}
+
+	for i, e := range g.Edges {
+		src, tr, err := printer.EdgeIDs(e, nodeIDs)
+		if err != nil {
+			return err
+		}
+
+		cells = append(cells, mxCell{
+			ID:     fmt.Sprintf("e%d", i),
+			Style:  edgeStyle,
+			Edge:   "1",
+			Parent: "1",
+			Source: src,
+			Target: tr,
+			Geometry: &mxGeometry{
+				Relative: "1",
+				As:       "geometry",
+			},
+		})
+	}
+
+	f := mxFile{
+		Host: "inframap",
+		Diagram: mxDiagram{
+			ID:   "inframap",
+			Name: "InfraMap",
+			Model: mxGraphModel{
+				Grid:     1,
+				GridSize: 10,
+				Arrows:   1,
+				Connect:  1,
+				Cells:    cells,
+			},
+		},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(f); err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+
+	return err
+}
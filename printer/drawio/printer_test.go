@@ -0,0 +1,74 @@
+package drawio_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/printer/drawio"
+	"github.com/cycloidio/tfdocs/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type cell struct {
+	ID     string `xml:"id,attr"`
+	Value  string `xml:"value,attr"`
+	Style  string `xml:"style,attr"`
+	Vertex string `xml:"vertex,attr"`
+	Edge   string `xml:"edge,attr"`
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+}
+
+type file struct {
+	Cells []cell `xml:"diagram>mxGraphModel>root>mxCell"`
+}
+
+func TestPrint(t *testing.T) {
+	g := graph.New()
+	n1 := &graph.Node{ID: "1", Canonical: "im_out.tcp/443->443", Resource: resource.Resource{Icon: "baseline_cloud_queue_black.svg"}}
+	n2 := &graph.Node{ID: "2", Canonical: "aws_security_group.front"}
+	n3 := &graph.Node{ID: "3", Canonical: "aws_instance.front"}
+
+	require.NoError(t, g.AddNode(n1))
+	require.NoError(t, g.AddNode(n2))
+	require.NoError(t, g.AddNode(n3))
+	require.NoError(t, g.AddEdge(&graph.Edge{ID: "1", Source: n1.ID, Target: n2.ID}))
+	require.NoError(t, g.AddEdge(&graph.Edge{ID: "2", Source: n2.ID, Target: n3.ID}))
+
+	t.Run("Success", func(t *testing.T) {
+		var buff bytes.Buffer
+		err := drawio.DrawIO{}.Print(g, printer.Options{}, &buff)
+		require.NoError(t, err)
+
+		var f file
+		err = xml.Unmarshal(buff.Bytes(), &f)
+		require.NoError(t, err)
+
+		require.Len(t, f.Cells, 7)
+		assert.Equal(t, cell{ID: "0"}, f.Cells[0])
+		assert.Equal(t, "im_out.tcp/443->443", f.Cells[2].Value)
+		assert.True(t, strings.HasPrefix(f.Cells[2].Style, "ellipse;"))
+		assert.True(t, strings.HasPrefix(f.Cells[3].Style, "rounded=0;"))
+		assert.Equal(t, "1", f.Cells[5].Edge)
+		assert.Equal(t, f.Cells[2].ID, f.Cells[5].Source)
+		assert.Equal(t, f.Cells[3].ID, f.Cells[5].Target)
+	})
+	t.Run("SuccessWithIcons", func(t *testing.T) {
+		var buff bytes.Buffer
+		err := drawio.DrawIO{}.Print(g, printer.Options{ShowIcons: true}, &buff)
+		require.NoError(t, err)
+
+		var f file
+		err = xml.Unmarshal(buff.Bytes(), &f)
+		require.NoError(t, err)
+
+		require.Len(t, f.Cells, 7)
+		assert.Contains(t, f.Cells[2].Style, "image=data:image/png,iVBOR")
+		assert.NotContains(t, f.Cells[3].Style, "image=")
+	})
+}
@@ -5,12 +5,38 @@ import (
 
 	"github.com/cycloidio/inframap/errcode"
 	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/printer/ascii"
+	"github.com/cycloidio/inframap/printer/cypher"
+	"github.com/cycloidio/inframap/printer/cytoscape"
+	"github.com/cycloidio/inframap/printer/d2"
 	"github.com/cycloidio/inframap/printer/dot"
+	"github.com/cycloidio/inframap/printer/drawio"
+	"github.com/cycloidio/inframap/printer/gexf"
+	"github.com/cycloidio/inframap/printer/graphml"
+	"github.com/cycloidio/inframap/printer/html"
+	"github.com/cycloidio/inframap/printer/json"
+	"github.com/cycloidio/inframap/printer/mermaid"
+	"github.com/cycloidio/inframap/printer/plantuml"
+	"github.com/cycloidio/inframap/printer/structurizr"
+	"github.com/cycloidio/inframap/printer/svg"
 )
 
 var (
 	printers = map[printer.Type]printer.Printer{
-		printer.DOT: dot.Dot{},
+		printer.DOT:         dot.Dot{},
+		printer.JSON:        json.JSON{},
+		printer.Mermaid:     mermaid.Mermaid{},
+		printer.DrawIO:      drawio.DrawIO{},
+		printer.SVG:         svg.SVG{},
+		printer.HTML:        html.HTML{},
+		printer.ASCII:       ascii.ASCII{},
+		printer.GraphML:     graphml.GraphML{},
+		printer.GEXF:        gexf.GEXF{},
+		printer.PlantUML:    plantuml.PlantUML{},
+		printer.D2:          d2.D2{},
+		printer.Cytoscape:   cytoscape.Cytoscape{},
+		printer.Structurizr: structurizr.Structurizr{},
+		printer.Cypher:      cypher.Cypher{},
 	}
 )
 
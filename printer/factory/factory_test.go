@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/cycloidio/inframap/errcode"
+	"github.com/cycloidio/inframap/printer"
 	"github.com/cycloidio/inframap/printer/factory"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -12,9 +13,11 @@ import (
 
 func TestGet(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
-		p, err := factory.Get("dot")
-		require.NoError(t, err)
-		assert.NotNil(t, p)
+		for _, pt := range printer.TypeStrings() {
+			p, err := factory.Get(pt)
+			require.NoError(t, err, pt)
+			assert.NotNil(t, p, pt)
+		}
 	})
 	t.Run("Error", func(t *testing.T) {
 		p, err := factory.Get("potato")
@@ -0,0 +1,152 @@
+package gexf
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/provider"
+	"github.com/cycloidio/inframap/provider/factory"
+)
+
+// GEXF is the struct that implements
+// the Printer of GEXF format
+type GEXF struct{}
+
+type document struct {
+	XMLName xml.Name `xml:"gexf"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	Version string   `xml:"version,attr"`
+	Graph   xmlGraph `xml:"graph"`
+}
+
+type xmlGraph struct {
+	DefaultEdgeType string       `xml:"defaultedgetype,attr"`
+	Mode            string       `xml:"mode,attr"`
+	Attributes      []attributes `xml:"attributes"`
+	Nodes           []xmlNode    `xml:"nodes>node"`
+	Edges           []xmlEdge    `xml:"edges>edge"`
+}
+
+type attributes struct {
+	Class      string      `xml:"class,attr"`
+	Attributes []attribute `xml:"attribute"`
+}
+
+type attribute struct {
+	ID    string `xml:"id,attr"`
+	Title string `xml:"title,attr"`
+	Type  string `xml:"type,attr"`
+}
+
+type xmlNode struct {
+	ID        string     `xml:"id,attr"`
+	Label     string     `xml:"label,attr"`
+	AttValues []attValue `xml:"attvalues>attvalue"`
+}
+
+type xmlEdge struct {
+	ID        string     `xml:"id,attr"`
+	Source    string     `xml:"source,attr"`
+	Target    string     `xml:"target,attr"`
+	AttValues []attValue `xml:"attvalues>attvalue"`
+}
+
+type attValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// List of the IDs of the attributes
+const (
+	attrProvider     = "provider"
+	attrResourceType = "resource_type"
+	attrModule       = "module"
+	attrTFID         = "tfid"
+	attrCanonicals   = "canonicals"
+)
+
+// Print prints into w the g in GEXF format, the canonicals
+// of the Edges are a 'liststring' so they are separated by '|'
+func (gx GEXF) Print(g *graph.Graph, opt printer.Options, w io.Writer) error {
+	labels, err := printer.Labels(g, opt)
+	if err != nil {
+		return err
+	}
+
+	doc := document{
+		XMLNS:   "http://gexf.net/1.3",
+		Version: "1.3",
+		Graph: xmlGraph{
+			DefaultEdgeType: "directed",
+			Mode:            "static",
+			Attributes: []attributes{
+				{
+					Class: "node",
+					Attributes: []attribute{
+						{ID: attrProvider, Title: "provider", Type: "string"},
+						{ID: attrResourceType, Title: "resource_type", Type: "string"},
+						{ID: attrModule, Title: "module", Type: "string"},
+						{ID: attrTFID, Title: "tfid", Type: "string"},
+					},
+				},
+				{
+					Class: "edge",
+					Attributes: []attribute{
+						{ID: attrCanonicals, Title: "canonicals", Type: "liststring"},
+					},
+				},
+			},
+			Nodes: make([]xmlNode, 0, len(g.Nodes)),
+			Edges: make([]xmlEdge, 0, len(g.Edges)),
+		},
+	}
+
+	for _, n := range g.Nodes {
+		// If it's nil the pv, it means we do not know it so we'll use
+		// the RawProvider.
+		pv, _, _ := factory.GetProviderAndResource(n.Canonical)
+		if pv == nil {
+			pv = provider.RawProvider{}
+		}
+		module, rt, _ := graph.ParseCanonical(n.Canonical)
+
+		doc.Graph.Nodes = append(doc.Graph.Nodes, xmlNode{
+			ID:    n.ID,
+			Label: labels[n.ID],
+			AttValues: []attValue{
+				{For: attrProvider, Value: pv.Type().String()},
+				{For: attrResourceType, Value: rt},
+				{For: attrModule, Value: module},
+				{For: attrTFID, Value: n.TFID},
+			},
+		})
+	}
+
+	for _, e := range g.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, xmlEdge{
+			ID:     e.ID,
+			Source: e.Source,
+			Target: e.Target,
+			AttValues: []attValue{
+				{For: attrCanonicals, Value: strings.Join(e.Canonicals, "|")},
+			},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+
+	return err
+}
@@ -0,0 +1,67 @@
+package gexf_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/printer/gexf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type attValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type file struct {
+	Nodes []struct {
+		ID        string     `xml:"id,attr"`
+		Label     string     `xml:"label,attr"`
+		AttValues []attValue `xml:"attvalues>attvalue"`
+	} `xml:"graph>nodes>node"`
+	Edges []struct {
+		Source    string     `xml:"source,attr"`
+		Target    string     `xml:"target,attr"`
+		AttValues []attValue `xml:"attvalues>attvalue"`
+	} `xml:"graph>edges>edge"`
+}
+
+func TestPrint(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		g := graph.New()
+		n1 := &graph.Node{ID: "1", Canonical: "module.app.aws_lb.front", TFID: "lb-id"}
+		n2 := &graph.Node{ID: "2", Canonical: "aws_instance.front", TFID: "i-id"}
+		e := &graph.Edge{ID: "3", Source: n1.ID, Target: n2.ID}
+		e.AddCanonicals("aws_security_group.front", "aws_security_group.back")
+
+		require.NoError(t, g.AddNode(n1))
+		require.NoError(t, g.AddNode(n2))
+		require.NoError(t, g.AddEdge(e))
+
+		var buff bytes.Buffer
+		err := gexf.GEXF{}.Print(g, printer.Options{}, &buff)
+		require.NoError(t, err)
+
+		var f file
+		err = xml.Unmarshal(buff.Bytes(), &f)
+		require.NoError(t, err)
+
+		require.Len(t, f.Nodes, 2)
+		assert.Equal(t, "module.app.aws_lb.front", f.Nodes[0].Label)
+		assert.Equal(t, []attValue{
+			{For: "provider", Value: "aws"},
+			{For: "resource_type", Value: "aws_lb"},
+			{For: "module", Value: "module.app"},
+			{For: "tfid", Value: "lb-id"},
+		}, f.Nodes[0].AttValues)
+
+		require.Len(t, f.Edges, 1)
+		assert.Equal(t, []attValue{
+			{For: "canonicals", Value: "aws_security_group.front|aws_security_group.back"},
+		}, f.Edges[0].AttValues)
+	})
+}
@@ -0,0 +1,131 @@
+package graphml
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/provider"
+	"github.com/cycloidio/inframap/provider/factory"
+)
+
+// GraphML is the struct that implements
+// the Printer of GraphML format
+type GraphML struct{}
+
+type document struct {
+	XMLName xml.Name `xml:"graphml"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	Keys    []key    `xml:"key"`
+	Graph   xmlGraph `xml:"graph"`
+}
+
+type key struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type xmlGraph struct {
+	ID          string    `xml:"id,attr"`
+	EdgeDefault string    `xml:"edgedefault,attr"`
+	Nodes       []xmlNode `xml:"node"`
+	Edges       []xmlEdge `xml:"edge"`
+}
+
+type xmlNode struct {
+	ID   string `xml:"id,attr"`
+	Data []data `xml:"data"`
+}
+
+type xmlEdge struct {
+	ID     string `xml:"id,attr"`
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+	Data   []data `xml:"data"`
+}
+
+type data struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// keys are all the attributes that
+// the Nodes and Edges have
+var keys = []key{
+	{ID: "label", For: "node", AttrName: "label", AttrType: "string"},
+	{ID: "provider", For: "node", AttrName: "provider", AttrType: "string"},
+	{ID: "resource_type", For: "node", AttrName: "resource_type", AttrType: "string"},
+	{ID: "module", For: "node", AttrName: "module", AttrType: "string"},
+	{ID: "tfid", For: "node", AttrName: "tfid", AttrType: "string"},
+	{ID: "canonicals", For: "edge", AttrName: "canonicals", AttrType: "string"},
+}
+
+// Print prints into w the g in GraphML format, the
+// canonicals of the Edges are separated by ','
+func (gm GraphML) Print(g *graph.Graph, opt printer.Options, w io.Writer) error {
+	labels, err := printer.Labels(g, opt)
+	if err != nil {
+		return err
+	}
+
+	doc := document{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys:  keys,
+		Graph: xmlGraph{
+			ID:          "G",
+			EdgeDefault: "directed",
+			Nodes:       make([]xmlNode, 0, len(g.Nodes)),
+			Edges:       make([]xmlEdge, 0, len(g.Edges)),
+		},
+	}
+
+	for _, n := range g.Nodes {
+		// If it's nil the pv, it means we do not know it so we'll use
+		// the RawProvider.
+		pv, _, _ := factory.GetProviderAndResource(n.Canonical)
+		if pv == nil {
+			pv = provider.RawProvider{}
+		}
+		module, rt, _ := graph.ParseCanonical(n.Canonical)
+
+		doc.Graph.Nodes = append(doc.Graph.Nodes, xmlNode{
+			ID: n.ID,
+			Data: []data{
+				{Key: "label", Value: labels[n.ID]},
+				{Key: "provider", Value: pv.Type().String()},
+				{Key: "resource_type", Value: rt},
+				{Key: "module", Value: module},
+				{Key: "tfid", Value: n.TFID},
+			},
+		})
+	}
+
+	for _, e := range g.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, xmlEdge{
+			ID:     e.ID,
+			Source: e.Source,
+			Target: e.Target,
+			Data: []data{
+				{Key: "canonicals", Value: strings.Join(e.Canonicals, ",")},
+			},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+
+	return err
+}
@@ -0,0 +1,69 @@
+package graphml_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/printer/graphml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type data struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+type file struct {
+	Nodes []struct {
+		ID   string `xml:"id,attr"`
+		Data []data `xml:"data"`
+	} `xml:"graph>node"`
+	Edges []struct {
+		Source string `xml:"source,attr"`
+		Target string `xml:"target,attr"`
+		Data   []data `xml:"data"`
+	} `xml:"graph>edge"`
+}
+
+func TestPrint(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		g := graph.New()
+		n1 := &graph.Node{ID: "1", Canonical: "module.app.aws_lb.front", TFID: "lb-id"}
+		n2 := &graph.Node{ID: "2", Canonical: "potato_instance.front", TFID: "i-id"}
+		e := &graph.Edge{ID: "3", Source: n1.ID, Target: n2.ID}
+		e.AddCanonicals("aws_security_group.front", "aws_security_group.back")
+
+		require.NoError(t, g.AddNode(n1))
+		require.NoError(t, g.AddNode(n2))
+		require.NoError(t, g.AddEdge(e))
+
+		var buff bytes.Buffer
+		err := graphml.GraphML{}.Print(g, printer.Options{}, &buff)
+		require.NoError(t, err)
+
+		var f file
+		err = xml.Unmarshal(buff.Bytes(), &f)
+		require.NoError(t, err)
+
+		require.Len(t, f.Nodes, 2)
+		assert.Equal(t, []data{
+			{Key: "label", Value: "module.app.aws_lb.front"},
+			{Key: "provider", Value: "aws"},
+			{Key: "resource_type", Value: "aws_lb"},
+			{Key: "module", Value: "module.app"},
+			{Key: "tfid", Value: "lb-id"},
+		}, f.Nodes[0].Data)
+		assert.Equal(t, "raw", f.Nodes[1].Data[1].Value)
+
+		require.Len(t, f.Edges, 1)
+		assert.Equal(t, "1", f.Edges[0].Source)
+		assert.Equal(t, "2", f.Edges[0].Target)
+		assert.Equal(t, []data{
+			{Key: "canonicals", Value: "aws_security_group.front,aws_security_group.back"},
+		}, f.Edges[0].Data)
+	})
+}
@@ -0,0 +1,37 @@
+package html
+
+import (
+	"bytes"
+	"html/template"
+	"io"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/printer/json"
+	"github.com/cycloidio/inframap/printer/svg"
+)
+
+// HTML is the struct that implements the Printer
+// of a standalone HTML page with a viewer of the graph
+type HTML struct{}
+
+var page = template.Must(template.New("html").Parse(tmpl))
+
+// Print prints into w the g as an HTML page that
+// can be used offline, the graph is drawn with the
+// svg.SVG and the data used for the details is
+// the one from json.JSON
+func (h HTML) Print(g *graph.Graph, opt printer.Options, w io.Writer) error {
+	var buff bytes.Buffer
+	if err := (svg.SVG{}).Print(g, opt, &buff); err != nil {
+		return err
+	}
+
+	return page.Execute(w, struct {
+		SVG  template.HTML
+		Data json.Graph
+	}{
+		SVG:  template.HTML(buff.String()),
+		Data: json.NewGraph(g),
+	})
+}
@@ -0,0 +1,40 @@
+package html_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/printer/html"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrint(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		g := graph.New()
+		n1 := &graph.Node{ID: "1", Canonical: "aws_lb.front", TFID: "lb-id"}
+		n2 := &graph.Node{ID: "2", Canonical: "aws_instance.front", TFID: "i-id"}
+		e := &graph.Edge{ID: "3", Source: n1.ID, Target: n2.ID}
+		e.AddCanonicals("aws_security_group.front")
+
+		require.NoError(t, g.AddNode(n1))
+		require.NoError(t, g.AddNode(n2))
+		require.NoError(t, g.AddEdge(e))
+
+		var buff bytes.Buffer
+		err := html.HTML{}.Print(g, printer.Options{}, &buff)
+		require.NoError(t, err)
+
+		out := buff.String()
+		assert.Contains(t, out, `<g class="node" data-id="1">`)
+		assert.Contains(t, out, `<polyline class="edge" data-id="3"`)
+		assert.Contains(t, out, `"canonicals":["aws_security_group.front"]`)
+		assert.Contains(t, out, `"tfid":"lb-id"`)
+
+		// Everything has to be inlined
+		assert.NotContains(t, out, "<script src")
+		assert.NotContains(t, out, "<link")
+	})
+}
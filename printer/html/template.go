@@ -0,0 +1,164 @@
+package html
+
+// tmpl is the HTML page with the viewer, it has all
+// the CSS and JS inlined so it can be used offline
+const tmpl = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>InfraMap</title>
+<style>
+  html, body { margin: 0; height: 100%; font-family: sans-serif; font-size: 14px; }
+  body { display: flex; }
+  #viewer { flex: 1; overflow: hidden; cursor: grab; background: #fafafa; }
+  #viewer.dragging { cursor: grabbing; }
+  #viewer svg { width: 100%; height: 100%; }
+  #viewer .node { cursor: pointer; }
+  #viewer .edge { cursor: pointer; stroke-width: 2; }
+  #viewer .edge:hover, #viewer .edge.selected { stroke: #d6336c; stroke-width: 4; }
+  #viewer .node.selected ellipse, #viewer .node.selected rect, #viewer .node.selected text { stroke: #d6336c; }
+  #panel { width: 360px; overflow: auto; padding: 12px 16px; border-left: 1px solid #dddddd; background: #ffffff; }
+  #panel h2 { font-size: 16px; word-break: break-all; }
+  #panel dt { font-weight: bold; margin-top: 8px; }
+  #panel dd { margin: 0; word-break: break-all; }
+  #panel ul { padding-left: 18px; margin: 4px 0; }
+  #panel .empty { color: #888888; }
+  #reset { margin-bottom: 8px; }
+</style>
+</head>
+<body>
+<div id="viewer">{{ .SVG }}</div>
+<div id="panel">
+  <button id="reset" type="button">Reset view</button>
+  <div id="details"><p class="empty">Click on a Node or an Edge to see its details.</p></div>
+</div>
+<script>
+(function () {
+  var data = {{ .Data }};
+  var nodes = {}, edges = {};
+  data.nodes.forEach(function (n) { nodes[n.id] = n; });
+  data.edges.forEach(function (e) { edges[e.id] = e; });
+
+  var viewer = document.getElementById('viewer');
+  var details = document.getElementById('details');
+  var svg = viewer.querySelector('svg');
+  var initial = svg.getAttribute('viewBox').split(' ').map(Number);
+  var box = initial.slice();
+  svg.removeAttribute('width');
+  svg.removeAttribute('height');
+
+  function apply() { svg.setAttribute('viewBox', box.join(' ')); }
+
+  function toSVG(ev) {
+    var pt = svg.createSVGPoint();
+    pt.x = ev.clientX;
+    pt.y = ev.clientY;
+    return pt.matrixTransform(svg.getScreenCTM().inverse());
+  }
+
+  svg.addEventListener('wheel', function (ev) {
+    ev.preventDefault();
+    var p = toSVG(ev);
+    var s = ev.deltaY < 0 ? 0.9 : 1.1;
+    box = [p.x - (p.x - box[0]) * s, p.y - (p.y - box[1]) * s, box[2] * s, box[3] * s];
+    apply();
+  }, { passive: false });
+
+  var drag = null, moved = false;
+  svg.addEventListener('mousedown', function (ev) {
+    drag = toSVG(ev);
+    moved = false;
+    viewer.classList.add('dragging');
+  });
+  window.addEventListener('mousemove', function (ev) {
+    if (!drag) { return; }
+    var p = toSVG(ev);
+    var dx = p.x - drag.x, dy = p.y - drag.y;
+    if (Math.abs(dx) + Math.abs(dy) > 0) { moved = true; }
+    box[0] -= dx;
+    box[1] -= dy;
+    apply();
+  });
+  window.addEventListener('mouseup', function () {
+    drag = null;
+    viewer.classList.remove('dragging');
+  });
+
+  document.getElementById('reset').addEventListener('click', function () {
+    box = initial.slice();
+    apply();
+  });
+
+  function el(tag, text, cls) {
+    var e = document.createElement(tag);
+    if (text !== undefined) { e.textContent = text; }
+    if (cls) { e.className = cls; }
+    return e;
+  }
+
+  function field(dl, name, value) {
+    dl.appendChild(el('dt', name));
+    dl.appendChild(el('dd', value || '-'));
+  }
+
+  function canonicals(e) {
+    if (e.canonicals.length === 0) {
+      return el('p', 'Direct connection', 'empty');
+    }
+    var ul = el('ul');
+    e.canonicals.forEach(function (c) { ul.appendChild(el('li', c)); });
+    return ul;
+  }
+
+  function select(id) {
+    Array.prototype.forEach.call(svg.querySelectorAll('.selected'), function (s) { s.classList.remove('selected'); });
+    Array.prototype.forEach.call(svg.querySelectorAll('[data-id]'), function (s) {
+      if (s.getAttribute('data-id') === id) { s.classList.add('selected'); }
+    });
+  }
+
+  function showNode(n) {
+    details.textContent = '';
+    details.appendChild(el('h2', n.canonical));
+    var dl = el('dl');
+    field(dl, 'TFID', n.tfid);
+    field(dl, 'Resource type', n.resource.type);
+    field(dl, 'Category', n.resource.category);
+    details.appendChild(dl);
+    details.appendChild(el('h3', 'Connections'));
+    var conns = data.edges.filter(function (e) { return e.source === n.id || e.target === n.id; });
+    if (conns.length === 0) {
+      details.appendChild(el('p', 'No connections', 'empty'));
+    }
+    conns.forEach(function (e) {
+      var text = e.source === n.id ? '→ ' + nodes[e.target].canonical : '← ' + nodes[e.source].canonical;
+      details.appendChild(el('h4', text));
+      details.appendChild(canonicals(e));
+    });
+    select(n.id);
+  }
+
+  function showEdge(e) {
+    details.textContent = '';
+    details.appendChild(el('h2', nodes[e.source].canonical + ' → ' + nodes[e.target].canonical));
+    details.appendChild(el('h3', 'Merged resources'));
+    details.appendChild(canonicals(e));
+    select(e.id);
+  }
+
+  svg.addEventListener('click', function (ev) {
+    if (moved) { return; }
+    var t = ev.target.closest('[data-id]');
+    if (!t) { return; }
+    var id = t.getAttribute('data-id');
+    if (nodes[id]) {
+      showNode(nodes[id]);
+    } else if (edges[id]) {
+      showEdge(edges[id]);
+    }
+  });
+})();
+</script>
+</body>
+</html>
+`
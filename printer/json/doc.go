@@ -0,0 +1,40 @@
+// Package json implements the printer.Printer that
+// outputs the graph.Graph in JSON format so it can
+// be consumed by other tools without having to parse
+// the DOT output.
+//
+// The output follows a versioned schema, the current
+// version is defined on SchemaVersion and any breaking
+// change on the format will increase it:
+//
+//	{
+//	  "version": "1",
+//	  "nodes": [
+//	    {
+//	      "id": "UUID of the Node",
+//	      "canonical": "module.front.aws_db_instance.front",
+//	      "tfid": "ID of the resource on Terraform",
+//	      "weight": 0,
+//	      "resource": {
+//	        "name": "",
+//	        "type": "aws_db_instance",
+//	        "category": "RDS",
+//	        "icon": "Database/Amazon-RDS.svg",
+//	        "metadata": {}
+//	      }
+//	    }
+//	  ],
+//	  "edges": [
+//	    {
+//	      "id": "UUID of the Edge",
+//	      "source": "UUID of the source Node",
+//	      "target": "UUID of the target Node",
+//	      "canonicals": ["module.front.aws_security_group.front"]
+//	    }
+//	  ]
+//	}
+//
+// The 'canonicals' of the Edge are the canonicals of all the resources
+// that have been merged into it, and 'metadata' is only present
+// if the Resource has any.
+package json
@@ -0,0 +1,102 @@
+package json
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+)
+
+// SchemaVersion is the version of the format
+// printed, it'll change if the format has
+// breaking changes
+const SchemaVersion = "1"
+
+// JSON is the struct that implements
+// the Printer of JSON format
+type JSON struct{}
+
+// Graph is the JSON representation of a graph.Graph
+type Graph struct {
+	Version string `json:"version"`
+	Nodes   []Node `json:"nodes"`
+	Edges   []Edge `json:"edges"`
+}
+
+// Node is the JSON representation of a graph.Node
+type Node struct {
+	ID        string   `json:"id"`
+	Canonical string   `json:"canonical"`
+	TFID      string   `json:"tfid"`
+	Weight    int      `json:"weight"`
+	Resource  Resource `json:"resource"`
+}
+
+// Resource is the JSON representation of the
+// resource information of the graph.Node
+type Resource struct {
+	Name     string                 `json:"name"`
+	Type     string                 `json:"type"`
+	Category string                 `json:"category"`
+	Icon     string                 `json:"icon"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// Edge is the JSON representation of a graph.Edge
+type Edge struct {
+	ID         string   `json:"id"`
+	Source     string   `json:"source"`
+	Target     string   `json:"target"`
+	Canonicals []string `json:"canonicals"`
+}
+
+// Print prints into w the g in JSON format
+func (j JSON) Print(g *graph.Graph, opt printer.Options, w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(NewGraph(g))
+}
+
+// NewGraph returns the JSON representation of g
+func NewGraph(g *graph.Graph) Graph {
+	jg := Graph{
+		Version: SchemaVersion,
+		Nodes:   make([]Node, 0, len(g.Nodes)),
+		Edges:   make([]Edge, 0, len(g.Edges)),
+	}
+
+	for _, n := range g.Nodes {
+		jg.Nodes = append(jg.Nodes, Node{
+			ID:        n.ID,
+			Canonical: n.Canonical,
+			TFID:      n.TFID,
+			Weight:    n.Weight,
+			Resource: Resource{
+				Name:     n.Resource.Name,
+				Type:     n.Resource.Type,
+				Category: n.Resource.Category,
+				Icon:     n.Resource.Icon,
+				Metadata: n.Resource.Metadata,
+			},
+		})
+	}
+
+	for _, e := range g.Edges {
+		// To always have an array on the output
+		// instead of a 'null'
+		cans := e.Canonicals
+		if cans == nil {
+			cans = make([]string, 0)
+		}
+		jg.Edges = append(jg.Edges, Edge{
+			ID:         e.ID,
+			Source:     e.Source,
+			Target:     e.Target,
+			Canonicals: cans,
+		})
+	}
+
+	return jg
+}
@@ -0,0 +1,54 @@
+package json_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	ijson "github.com/cycloidio/inframap/printer/json"
+	"github.com/cycloidio/tfdocs/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrint(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		g := graph.New()
+		n1 := &graph.Node{ID: "1", Canonical: "aws_lb.front", TFID: "lb-id", Resource: resource.Resource{Name: "aws_lb", Type: "aws_lb"}}
+		n2 := &graph.Node{ID: "2", Canonical: "aws_instance.front", TFID: "i-id", Weight: 1}
+		e := &graph.Edge{ID: "3", Source: n1.ID, Target: n2.ID}
+		e.AddCanonicals("aws_security_group.front")
+
+		require.NoError(t, g.AddNode(n1))
+		require.NoError(t, g.AddNode(n2))
+		require.NoError(t, g.AddEdge(e))
+
+		var buff bytes.Buffer
+		err := ijson.JSON{}.Print(g, printer.Options{}, &buff)
+		require.NoError(t, err)
+
+		var jg ijson.Graph
+		err = json.Unmarshal(buff.Bytes(), &jg)
+		require.NoError(t, err)
+
+		assert.Equal(t, ijson.Graph{
+			Version: ijson.SchemaVersion,
+			Nodes: []ijson.Node{
+				{ID: "1", Canonical: "aws_lb.front", TFID: "lb-id", Resource: ijson.Resource{Name: "aws_lb", Type: "aws_lb"}},
+				{ID: "2", Canonical: "aws_instance.front", TFID: "i-id", Weight: 1},
+			},
+			Edges: []ijson.Edge{
+				{ID: "3", Source: "1", Target: "2", Canonicals: []string{"aws_security_group.front"}},
+			},
+		}, jg)
+	})
+	t.Run("SuccessEmpty", func(t *testing.T) {
+		var buff bytes.Buffer
+		err := ijson.JSON{}.Print(graph.New(), printer.Options{}, &buff)
+		require.NoError(t, err)
+
+		assert.JSONEq(t, `{"version":"1","nodes":[],"edges":[]}`, buff.String())
+	})
+}
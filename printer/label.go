@@ -0,0 +1,127 @@
+package printer
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/cycloidio/inframap/errcode"
+	"github.com/cycloidio/inframap/graph"
+)
+
+// LabelData is the data of a Node that can
+// be used on the Options.LabelTemplate
+type LabelData struct {
+	// Canonical it's 'module.app.aws_lb.front'
+	Canonical string
+
+	// Module it's the module path 'module.app'
+	Module string
+
+	// Type it's the resource type 'aws_lb'
+	Type string
+
+	// Name it's the name 'front'
+	Name string
+
+	// TFID it's the internal ID it has on TF
+	TFID string
+
+	// Attributes are the attributes kept
+	// on the graph.Node.Attributes
+	Attributes map[string]interface{}
+
+	// Instances it's the number of instances
+	// collapsed into the Node, 0 if none
+	Instances int
+}
+
+// Attr returns the value of the attribute path (ex: 'tags.Name')
+// or an empty string if it was not kept on the Node
+func (ld LabelData) Attr(path string) string {
+	v, ok := ld.Attributes[path]
+	if !ok || v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// reLabelAttr matches the '.Attr "tags.Name"' on the templates
+var reLabelAttr = regexp.MustCompile(`\.Attr\s+"([^"]+)"`)
+
+// labelFuncs are the functions that can be used on
+// the templates, the value is always the last argument
+// so they can be used on pipelines
+var labelFuncs = template.FuncMap{
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"trimPrefix": func(p, v string) string {
+		return strings.TrimPrefix(v, p)
+	},
+	"replace": func(o, n, v string) string {
+		return strings.ReplaceAll(v, o, n)
+	},
+	"default": func(d, v string) string {
+		if v == "" {
+			return d
+		}
+		return v
+	},
+}
+
+// Labels returns the label of each Node of the g (graph.Node.ID -> label)
+// built with the opt.LabelTemplate. If it's empty the label
+// is the graph.Node.Canonical with the number of instances
+// if they were collapsed, like 'aws_instance.web x10'
+func Labels(g *graph.Graph, opt Options) (map[string]string, error) {
+	labels := make(map[string]string, len(g.Nodes))
+	if opt.LabelTemplate == "" {
+		for _, n := range g.Nodes {
+			labels[n.ID] = n.Canonical
+			if n.Instances > 1 {
+				labels[n.ID] = fmt.Sprintf("%s x%d", n.Canonical, n.Instances)
+			}
+		}
+		return labels, nil
+	}
+
+	tmpl, err := template.New("label").Funcs(labelFuncs).Parse(opt.LabelTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", err, errcode.ErrPrinterInvalidLabelTemplate)
+	}
+
+	var buff bytes.Buffer
+	for _, n := range g.Nodes {
+		module, rtype, name := graph.ParseCanonical(n.Canonical)
+
+		buff.Reset()
+		err = tmpl.Execute(&buff, LabelData{
+			Canonical:  n.Canonical,
+			Module:     module,
+			Type:       rtype,
+			Name:       name,
+			TFID:       n.TFID,
+			Attributes: n.Attributes,
+			Instances:  n.Instances,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("node %q: %s: %w", n.Canonical, err, errcode.ErrPrinterInvalidLabelTemplate)
+		}
+
+		labels[n.ID] = buff.String()
+	}
+
+	return labels, nil
+}
+
+// LabelAttributes returns the paths of the attributes used
+// on the tmpl with '.Attr', so the generator can keep them
+func LabelAttributes(tmpl string) []string {
+	attrs := make([]string, 0)
+	for _, m := range reLabelAttr.FindAllStringSubmatch(tmpl, -1) {
+		attrs = append(attrs, m[1])
+	}
+	return attrs
+}
@@ -0,0 +1,82 @@
+package printer_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cycloidio/inframap/errcode"
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabels(t *testing.T) {
+	g := graph.New()
+	nodes := []*graph.Node{
+		{ID: "1", Canonical: "module.app.module.db.aws_db_instance.main", TFID: "db-1", Attributes: map[string]interface{}{"tags.Name": "database"}},
+		{ID: "2", Canonical: "aws_lb.front", TFID: "lb-1"},
+	}
+	for _, n := range nodes {
+		require.NoError(t, g.AddNode(n))
+	}
+
+	t.Run("SuccessDefault", func(t *testing.T) {
+		labels, err := printer.Labels(g, printer.Options{})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"1": "module.app.module.db.aws_db_instance.main",
+			"2": "aws_lb.front",
+		}, labels)
+	})
+	t.Run("SuccessTemplate", func(t *testing.T) {
+		labels, err := printer.Labels(g, printer.Options{LabelTemplate: `{{.Name}} ({{trimPrefix "aws_" .Type | upper}}) {{.Module}} {{.TFID}}`})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"1": "main (DB_INSTANCE) module.app.module.db db-1",
+			"2": "front (LB)  lb-1",
+		}, labels)
+	})
+	t.Run("SuccessAttr", func(t *testing.T) {
+		labels, err := printer.Labels(g, printer.Options{LabelTemplate: `{{.Attr "tags.Name" | default .Canonical}}`})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"1": "database",
+			"2": "aws_lb.front",
+		}, labels)
+	})
+	t.Run("SuccessInstances", func(t *testing.T) {
+		g := graph.New()
+		require.NoError(t, g.AddNode(&graph.Node{ID: "1", Canonical: "aws_instance.web", Instances: 10}))
+		require.NoError(t, g.AddNode(&graph.Node{ID: "2", Canonical: "aws_lb.front", Instances: 1}))
+
+		labels, err := printer.Labels(g, printer.Options{})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"1": "aws_instance.web x10",
+			"2": "aws_lb.front",
+		}, labels)
+
+		labels, err = printer.Labels(g, printer.Options{LabelTemplate: `{{.Name}} ({{.Instances}})`})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"1": "web (10)",
+			"2": "front (1)",
+		}, labels)
+	})
+	t.Run("ErrorInvalidTemplate", func(t *testing.T) {
+		_, err := printer.Labels(g, printer.Options{LabelTemplate: `{{.Name`})
+		assert.True(t, errors.Is(err, errcode.ErrPrinterInvalidLabelTemplate))
+	})
+	t.Run("ErrorExecute", func(t *testing.T) {
+		_, err := printer.Labels(g, printer.Options{LabelTemplate: `{{.Potato}}`})
+		assert.True(t, errors.Is(err, errcode.ErrPrinterInvalidLabelTemplate))
+	})
+}
+
+func TestLabelAttributes(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		assert.Equal(t, []string{"tags.Name", "name"}, printer.LabelAttributes(`{{.Attr "tags.Name"}} {{ .Attr  "name" }} {{.Type}}`))
+		assert.Equal(t, []string{}, printer.LabelAttributes(`{{.Type}}`))
+	})
+}
@@ -0,0 +1,72 @@
+package mermaid
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/provider"
+	"github.com/cycloidio/inframap/provider/factory"
+)
+
+// Mermaid is the struct that implements
+// the Printer of Mermaid flowchart format
+type Mermaid struct{}
+
+// labelReplacer escapes the characters that have
+// meaning inside a Mermaid label
+var labelReplacer = strings.NewReplacer(
+	`"`, "#quot;",
+	"<", "#lt;",
+	">", "#gt;",
+)
+
+// Print prints into w the g in Mermaid flowchart format
+func (m Mermaid) Print(g *graph.Graph, opt printer.Options, w io.Writer) error {
+	labels, err := printer.Labels(g, opt)
+	if err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("flowchart TB\n")
+
+	// nodeIDs holds graph.Node.ID -> Mermaid ID, the
+	// Mermaid IDs are generated as the Canonicals
+	// can have characters not valid for Mermaid
+	nodeIDs := make(map[string]string, len(g.Nodes))
+	for i, n := range g.Nodes {
+		// If it's nil the pv, it means we do not know it so we'll use
+		// the RawProvider.
+		pv, rs, _ := factory.GetProviderAndResource(n.Canonical)
+		if pv == nil {
+			pv = provider.RawProvider{}
+		}
+
+		id := fmt.Sprintf("n%d", i)
+		nodeIDs[n.ID] = id
+
+		label := labelReplacer.Replace(labels[n.ID])
+		if pv.IsEdge(rs) {
+			fmt.Fprintf(&sb, "    %s[\"%s\"]\n", id, label)
+		} else {
+			fmt.Fprintf(&sb, "    %s([\"%s\"])\n", id, label)
+		}
+	}
+
+	for _, e := range g.Edges {
+		src, tr, err := printer.EdgeIDs(e, nodeIDs)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(&sb, "    %s --> %s\n", src, tr)
+	}
+
+	_, err = io.WriteString(w, sb.String())
+
+	return err
+}
@@ -0,0 +1,44 @@
+package mermaid_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/printer/mermaid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrint(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		g := graph.New()
+		n1 := &graph.Node{ID: "1", Canonical: "im_out.tcp/443->443"}
+		n2 := &graph.Node{ID: "2", Canonical: "module.front.aws_lb.front"}
+		n3 := &graph.Node{ID: "3", Canonical: "aws_security_group.front"}
+		n4 := &graph.Node{ID: "4", Canonical: "aws_instance.front"}
+
+		require.NoError(t, g.AddNode(n1))
+		require.NoError(t, g.AddNode(n2))
+		require.NoError(t, g.AddNode(n3))
+		require.NoError(t, g.AddNode(n4))
+		require.NoError(t, g.AddEdge(&graph.Edge{ID: "1", Source: n1.ID, Target: n2.ID}))
+		require.NoError(t, g.AddEdge(&graph.Edge{ID: "2", Source: n2.ID, Target: n3.ID}))
+		require.NoError(t, g.AddEdge(&graph.Edge{ID: "3", Source: n3.ID, Target: n4.ID}))
+
+		var buff bytes.Buffer
+		err := mermaid.Mermaid{}.Print(g, printer.Options{}, &buff)
+		require.NoError(t, err)
+
+		assert.Equal(t, `flowchart TB
+    n0(["im_out.tcp/443-#gt;443"])
+    n1(["module.front.aws_lb.front"])
+    n2["aws_security_group.front"]
+    n3(["aws_instance.front"])
+    n0 --> n1
+    n1 --> n2
+    n2 --> n3
+`, buff.String())
+	})
+}
@@ -6,4 +6,27 @@ type Options struct {
 	// ShowIcons toggles display of the
 	// icons on the end Graph
 	ShowIcons bool
+
+	// PlainASCII makes the text printers
+	// use only ASCII characters
+	PlainASCII bool
+
+	// Width is the maximum number of columns
+	// of the text printers, 0 means the default
+	// of each printer
+	Width int
+
+	// StackName is the name of the stack the
+	// graph represents, used by the printers that
+	// could hold more than one stack
+	StackName string
+
+	// Theme is the styling of the printed Graph,
+	// if nil the default of each printer is used
+	Theme *Theme
+
+	// LabelTemplate is the Go text/template used
+	// to build the label of each Node from a LabelData,
+	// if empty the Canonical is used
+	LabelTemplate string
 }
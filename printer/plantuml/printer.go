@@ -0,0 +1,137 @@
+package plantuml
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/provider"
+	"github.com/cycloidio/inframap/provider/factory"
+)
+
+// PlantUML is the struct that implements the Printer
+// of PlantUML deployment diagram format
+type PlantUML struct{}
+
+// List of the PlantUML deployment elements used
+const (
+	elementNode      = "node"
+	elementDatabase  = "database"
+	elementQueue     = "queue"
+	elementStorage   = "storage"
+	elementCloud     = "cloud"
+	elementRectangle = "rectangle"
+)
+
+// elementKeywords are checked in order against the
+// resource type and the first one that is contained
+// on it defines the element, if none matches
+// it'll be a 'node'
+var elementKeywords = []struct {
+	keyword string
+	element string
+}{
+	{keyword: "_db_", element: elementDatabase},
+	{keyword: "database", element: elementDatabase},
+	{keyword: "rds", element: elementDatabase},
+	{keyword: "sql", element: elementDatabase},
+	{keyword: "dynamodb", element: elementDatabase},
+	{keyword: "elasticache", element: elementDatabase},
+	{keyword: "redis", element: elementDatabase},
+	{keyword: "redshift", element: elementDatabase},
+	{keyword: "neptune", element: elementDatabase},
+	{keyword: "dax", element: elementDatabase},
+	{keyword: "cosmosdb", element: elementDatabase},
+	{keyword: "mariadb", element: elementDatabase},
+	{keyword: "spanner", element: elementDatabase},
+	{keyword: "bigtable", element: elementDatabase},
+	{keyword: "bigquery", element: elementDatabase},
+	{keyword: "elasticsearch", element: elementDatabase},
+	{keyword: "dcs_instance", element: elementDatabase},
+	{keyword: "dds_instance", element: elementDatabase},
+	{keyword: "queue", element: elementQueue},
+	{keyword: "sqs", element: elementQueue},
+	{keyword: "sns", element: elementQueue},
+	{keyword: "mq_broker", element: elementQueue},
+	{keyword: "kinesis", element: elementQueue},
+	{keyword: "pubsub", element: elementQueue},
+	{keyword: "smn_topic", element: elementQueue},
+	{keyword: "bucket", element: elementStorage},
+	{keyword: "storage_container", element: elementStorage},
+	{keyword: "objectstorage", element: elementStorage},
+	{keyword: "ebs_volume", element: elementStorage},
+	{keyword: "efs_file_system", element: elementStorage},
+	{keyword: "sfs_file_system", element: elementStorage},
+	{keyword: "filestore", element: elementStorage},
+	{keyword: "disk", element: elementStorage},
+	{keyword: "volume", element: elementStorage},
+}
+
+// Print prints into w the g in PlantUML deployment diagram format
+func (p PlantUML) Print(g *graph.Graph, opt printer.Options, w io.Writer) error {
+	labels, err := printer.Labels(g, opt)
+	if err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("@startuml\n")
+
+	// nodeIDs holds graph.Node.ID -> PlantUML alias
+	nodeIDs := make(map[string]string, len(g.Nodes))
+	for i, n := range g.Nodes {
+		// If it's nil the pv, it means we do not know it so we'll use
+		// the RawProvider.
+		pv, rs, _ := factory.GetProviderAndResource(n.Canonical)
+		if pv == nil {
+			pv = provider.RawProvider{}
+		}
+
+		id := fmt.Sprintf("n%d", i)
+		nodeIDs[n.ID] = id
+
+		// PlantUML does not support escaping the '"' so
+		// we replace them to not break the label
+		label := strings.ReplaceAll(labels[n.ID], `"`, "'")
+
+		fmt.Fprintf(&sb, "%s \"%s\" as %s\n", element(pv, rs), label, id)
+	}
+
+	for _, e := range g.Edges {
+		src, tr, err := printer.EdgeIDs(e, nodeIDs)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(&sb, "%s --> %s\n", src, tr)
+	}
+
+	sb.WriteString("@enduml\n")
+
+	_, err = io.WriteString(w, sb.String())
+
+	return err
+}
+
+// element returns the PlantUML deployment element
+// that represents the rs of the pv
+func element(pv provider.Provider, rs string) string {
+	if pv.Type() == provider.IM {
+		return elementCloud
+	}
+
+	if pv.IsEdge(rs) {
+		return elementRectangle
+	}
+
+	for _, ek := range elementKeywords {
+		if strings.Contains(rs, ek.keyword) {
+			return ek.element
+		}
+	}
+
+	return elementNode
+}
@@ -0,0 +1,51 @@
+package plantuml_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/printer/plantuml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrint(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		g := graph.New()
+		nodes := []*graph.Node{
+			{ID: "1", Canonical: "im_out.tcp/443->443"},
+			{ID: "2", Canonical: "aws_lb.front"},
+			{ID: "3", Canonical: "aws_security_group.front"},
+			{ID: "4", Canonical: "module.app.aws_db_instance.main"},
+			{ID: "5", Canonical: "aws_sqs_queue.jobs"},
+			{ID: "6", Canonical: "aws_s3_bucket.assets"},
+		}
+		for _, n := range nodes {
+			require.NoError(t, g.AddNode(n))
+		}
+		require.NoError(t, g.AddEdge(&graph.Edge{ID: "1", Source: "1", Target: "2"}))
+		require.NoError(t, g.AddEdge(&graph.Edge{ID: "2", Source: "2", Target: "3"}))
+		require.NoError(t, g.AddEdge(&graph.Edge{ID: "3", Source: "3", Target: "4"}))
+		require.NoError(t, g.AddEdge(&graph.Edge{ID: "4", Source: "2", Target: "5"}))
+
+		var buff bytes.Buffer
+		err := plantuml.PlantUML{}.Print(g, printer.Options{}, &buff)
+		require.NoError(t, err)
+
+		assert.Equal(t, `@startuml
+cloud "im_out.tcp/443->443" as n0
+node "aws_lb.front" as n1
+rectangle "aws_security_group.front" as n2
+database "module.app.aws_db_instance.main" as n3
+queue "aws_sqs_queue.jobs" as n4
+storage "aws_s3_bucket.assets" as n5
+n0 --> n1
+n1 --> n2
+n2 --> n3
+n1 --> n4
+@enduml
+`, buff.String())
+	})
+}
@@ -1,8 +1,10 @@
 package printer
 
 import (
+	"fmt"
 	"io"
 
+	"github.com/cycloidio/inframap/errcode"
 	"github.com/cycloidio/inframap/graph"
 )
 
@@ -11,3 +13,20 @@ import (
 type Printer interface {
 	Print(g *graph.Graph, opt Options, w io.Writer) error
 }
+
+// EdgeIDs returns the IDs of the Source and the Target of the e from
+// the ids (graph.Node.ID -> ID on the format) given by the Printer,
+// if any of them is missing it returns errcode.ErrGraphNotFoundNode
+func EdgeIDs(e *graph.Edge, ids map[string]string) (string, string, error) {
+	src, ok := ids[e.Source]
+	if !ok {
+		return "", "", fmt.Errorf("edge source %q: %w", e.Source, errcode.ErrGraphNotFoundNode)
+	}
+
+	tr, ok := ids[e.Target]
+	if !ok {
+		return "", "", fmt.Errorf("edge target %q: %w", e.Target, errcode.ErrGraphNotFoundNode)
+	}
+
+	return src, tr, nil
+}
@@ -0,0 +1,31 @@
+package printer_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cycloidio/inframap/errcode"
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEdgeIDs(t *testing.T) {
+	ids := map[string]string{"1": "n0", "2": "n1"}
+
+	t.Run("Success", func(t *testing.T) {
+		src, tr, err := printer.EdgeIDs(&graph.Edge{Source: "1", Target: "2"}, ids)
+		require.NoError(t, err)
+		assert.Equal(t, "n0", src)
+		assert.Equal(t, "n1", tr)
+	})
+	t.Run("ErrorNotFoundSource", func(t *testing.T) {
+		_, _, err := printer.EdgeIDs(&graph.Edge{Source: "3", Target: "2"}, ids)
+		assert.True(t, errors.Is(err, errcode.ErrGraphNotFoundNode))
+	})
+	t.Run("ErrorNotFoundTarget", func(t *testing.T) {
+		_, _, err := printer.EdgeIDs(&graph.Edge{Source: "1", Target: "3"}, ids)
+		assert.True(t, errors.Is(err, errcode.ErrGraphNotFoundNode))
+	})
+}
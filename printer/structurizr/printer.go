@@ -0,0 +1,176 @@
+package structurizr
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/provider"
+	"github.com/cycloidio/inframap/provider/factory"
+)
+
+// Structurizr is the struct that implements
+// the Printer of Structurizr DSL format
+type Structurizr struct{}
+
+const (
+	// systemID is the identifier of the software system
+	// that holds all the Nodes as containers
+	systemID = "infrastructure"
+
+	// internetID is the identifier of the external software
+	// system that represents all the 'im_out' Nodes
+	internetID = "internet"
+)
+
+// group is a Terraform module which holds
+// the containers and the subgroups
+type group struct {
+	name string
+
+	groups []*group
+	byName map[string]*group
+
+	containers []string
+}
+
+// get returns the subgroup with the name, creating
+// it if it does not exists yet
+func (gr *group) get(name string) *group {
+	if sg, ok := gr.byName[name]; ok {
+		return sg
+	}
+
+	sg := &group{
+		name:   name,
+		byName: make(map[string]*group),
+	}
+	gr.byName[name] = sg
+	gr.groups = append(gr.groups, sg)
+
+	return sg
+}
+
+// write writes the containers of gr and
+// the subgroups with the indent
+func (gr *group) write(sb *strings.Builder, indent string) {
+	for _, c := range gr.containers {
+		fmt.Fprintf(sb, "%s%s\n", indent, c)
+	}
+
+	for _, sg := range gr.groups {
+		fmt.Fprintf(sb, "%sgroup %s {\n", indent, quote(sg.name))
+		sg.write(sb, indent+"    ")
+		fmt.Fprintf(sb, "%s}\n", indent)
+	}
+}
+
+// Print prints into w the g in Structurizr DSL format as a workspace
+// with one software system in which the Nodes are containers grouped
+// by module. All the 'im_out' Nodes are represented by one external
+// software system and the relationships with it are described by
+// the name of the Node (ex: 'tcp/443->443')
+func (s Structurizr) Print(g *graph.Graph, opt printer.Options, w io.Writer) error {
+	root := &group{byName: make(map[string]*group)}
+
+	// nodeIDs holds graph.Node.ID -> Structurizr identifier
+	nodeIDs := make(map[string]string, len(g.Nodes))
+
+	// internetNames holds graph.Node.ID -> name of the 'im_out'
+	internetNames := make(map[string]string)
+
+	for i, n := range g.Nodes {
+		// If it's nil the pv, it means we do not know it so we'll use
+		// the RawProvider.
+		pv, _, _ := factory.GetProviderAndResource(n.Canonical)
+		if pv == nil {
+			pv = provider.RawProvider{}
+		}
+
+		module, rt, name := graph.ParseCanonical(n.Canonical)
+
+		if pv.Type() == provider.IM {
+			nodeIDs[n.ID] = internetID
+			internetNames[n.ID] = name
+			continue
+		}
+
+		gr := root
+		if module != "" {
+			// The module is 'module.NAME.module.NAME' and the
+			// NAME could have '.' if it has an instance key
+			for _, mn := range strings.Split(strings.TrimPrefix(module, "module."), ".module.") {
+				gr = gr.get(mn)
+			}
+		}
+
+		id := fmt.Sprintf("n%d", i)
+		nodeIDs[n.ID] = id
+
+		// The names of the containers have to be unique inside
+		// of the software system so we use the Canonical
+		gr.containers = append(gr.containers, fmt.Sprintf("%s = container %s %s %s", id, quote(n.Canonical), quote(n.TFID), quote(rt)))
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("workspace {\n")
+	sb.WriteString("    model {\n")
+	sb.WriteString("        properties {\n")
+	sb.WriteString("            \"structurizr.groupSeparator\" \"/\"\n")
+	sb.WriteString("        }\n")
+
+	if len(internetNames) > 0 {
+		fmt.Fprintf(&sb, "        %s = softwareSystem \"Internet\" \"\" \"External\"\n", internetID)
+	}
+
+	fmt.Fprintf(&sb, "        %s = softwareSystem \"Infrastructure\" {\n", systemID)
+	root.write(&sb, "            ")
+	sb.WriteString("        }\n")
+
+	for _, e := range g.Edges {
+		src, tr, err := printer.EdgeIDs(e, nodeIDs)
+		if err != nil {
+			return err
+		}
+
+		desc := strings.Join(e.Canonicals, ", ")
+		if n, ok := internetNames[e.Source]; ok {
+			desc = n
+		} else if n, ok := internetNames[e.Target]; ok {
+			desc = n
+		}
+
+		fmt.Fprintf(&sb, "        %s -> %s %s\n", src, tr, quote(desc))
+	}
+
+	sb.WriteString("    }\n")
+	sb.WriteString("    views {\n")
+	fmt.Fprintf(&sb, "        container %s {\n", systemID)
+	sb.WriteString("            include *\n")
+	sb.WriteString("            autoLayout\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("        styles {\n")
+	sb.WriteString("            element \"External\" {\n")
+	sb.WriteString("                background #999999\n")
+	sb.WriteString("            }\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("}\n")
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+// quoteReplacer escapes the s to be on a Structurizr DSL double quoted
+// string, on which only the '"' can be escaped and as the DSL is line
+// based the line breaks are replaced by spaces
+var quoteReplacer = strings.NewReplacer(`"`, `\"`, "\r\n", " ", "\n", " ")
+
+// quote returns the s as a Structurizr DSL double quoted string
+func quote(s string) string {
+	return fmt.Sprintf("\"%s\"", quoteReplacer.Replace(s))
+}
@@ -0,0 +1,79 @@
+package structurizr_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/printer/structurizr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrint(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		g := graph.New()
+		nodes := []*graph.Node{
+			{ID: "1", Canonical: "im_out.tcp/443->443"},
+			{ID: "2", Canonical: "module.app.aws_lb.front", TFID: "lb-id"},
+			{ID: "3", Canonical: "module.app.module.db.aws_db_instance.main", TFID: "db-id"},
+			{ID: "4", Canonical: "aws_instance.bastion", TFID: "i-id"},
+		}
+		for _, n := range nodes {
+			require.NoError(t, g.AddNode(n))
+		}
+		e := &graph.Edge{ID: "2", Source: "2", Target: "3"}
+		e.AddCanonicals("module.app.aws_security_group.front")
+		require.NoError(t, g.AddEdge(&graph.Edge{ID: "1", Source: "1", Target: "2"}))
+		require.NoError(t, g.AddEdge(e))
+		require.NoError(t, g.AddEdge(&graph.Edge{ID: "3", Source: "4", Target: "3"}))
+
+		var buff bytes.Buffer
+		err := structurizr.Structurizr{}.Print(g, printer.Options{}, &buff)
+		require.NoError(t, err)
+
+		assert.Equal(t, `workspace {
+    model {
+        properties {
+            "structurizr.groupSeparator" "/"
+        }
+        internet = softwareSystem "Internet" "" "External"
+        infrastructure = softwareSystem "Infrastructure" {
+            n3 = container "aws_instance.bastion" "i-id" "aws_instance"
+            group "app" {
+                n1 = container "module.app.aws_lb.front" "lb-id" "aws_lb"
+                group "db" {
+                    n2 = container "module.app.module.db.aws_db_instance.main" "db-id" "aws_db_instance"
+                }
+            }
+        }
+        internet -> n1 "tcp/443->443"
+        n1 -> n2 "module.app.aws_security_group.front"
+        n3 -> n2 ""
+    }
+    views {
+        container infrastructure {
+            include *
+            autoLayout
+        }
+        styles {
+            element "External" {
+                background #999999
+            }
+        }
+    }
+}
+`, buff.String())
+	})
+	t.Run("SuccessEscape", func(t *testing.T) {
+		g := graph.New()
+		require.NoError(t, g.AddNode(&graph.Node{ID: "1", Canonical: "aws_instance.bastion", TFID: "say \"hi\"\nbye"}))
+
+		var buff bytes.Buffer
+		err := structurizr.Structurizr{}.Print(g, printer.Options{}, &buff)
+		require.NoError(t, err)
+
+		assert.Contains(t, buff.String(), `n0 = container "aws_instance.bastion" "say \"hi\" bye" "aws_instance"`+"\n")
+	})
+}
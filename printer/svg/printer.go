@@ -0,0 +1,166 @@
+package svg
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cycloidio/inframap/assets"
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/layout"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/provider"
+	"github.com/cycloidio/inframap/provider/factory"
+)
+
+const (
+	// margin is the space around the graph
+	margin = 20
+
+	// iconSize is the width and height of the icons
+	iconSize = 48
+
+	// fontSize is the size of the labels and charWidth
+	// is the approximate width of each character
+	// used to calculate the width of the Nodes
+	fontSize  = 12
+	charWidth = 7
+
+	// lineHeight is the height reserved for the label
+	lineHeight = 20
+
+	// shapeHeight is the height of the Nodes without icon
+	shapeHeight = 40
+
+	nodeSpacing  = 30
+	layerSpacing = 60
+)
+
+// SVG is the struct that implements
+// the Printer of SVG format
+type SVG struct{}
+
+// Print prints into w the g in SVG format, it lays out the
+// graph so it does not need any external tool
+func (s SVG) Print(g *graph.Graph, opt printer.Options, w io.Writer) error {
+	labels, err := printer.Labels(g, opt)
+	if err != nil {
+		return err
+	}
+
+	width := iconSize
+	for _, n := range g.Nodes {
+		if l := len(labels[n.ID])*charWidth + 2*charWidth; l > width {
+			width = l
+		}
+	}
+
+	height := shapeHeight
+	if opt.ShowIcons {
+		height = iconSize + lineHeight
+	}
+
+	l := layout.Layered(g, layout.Options{
+		NodeWidth:    width,
+		NodeHeight:   height,
+		NodeSpacing:  nodeSpacing,
+		LayerSpacing: layerSpacing,
+	})
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\" font-family=\"sans-serif\" font-size=\"%d\">\n", l.Width+2*margin, l.Height+2*margin, l.Width+2*margin, l.Height+2*margin, fontSize)
+	sb.WriteString("  <defs>\n")
+	sb.WriteString("    <marker id=\"arrow\" viewBox=\"0 0 10 10\" refX=\"10\" refY=\"5\" markerWidth=\"8\" markerHeight=\"8\" orient=\"auto-start-reverse\">\n")
+	sb.WriteString("      <path d=\"M 0 0 L 10 5 L 0 10 z\" fill=\"#555555\"/>\n")
+	sb.WriteString("    </marker>\n")
+	sb.WriteString("  </defs>\n")
+	fmt.Fprintf(&sb, "  <g transform=\"translate(%d,%d)\">\n", margin, margin)
+
+	for _, e := range g.Edges {
+		points := l.Edges[e.ID]
+		if len(points) < 2 {
+			continue
+		}
+
+		// The points are the centers of the Nodes so the first
+		// and last ones are moved to the border of the Node
+		start := []int{points[0][0], points[0][1]}
+		end := []int{points[len(points)-1][0], points[len(points)-1][1]}
+		if points[1][1] >= start[1] {
+			start[1] += height / 2
+		} else {
+			start[1] -= height / 2
+		}
+		if points[len(points)-2][1] <= end[1] {
+			end[1] -= height / 2
+		} else {
+			end[1] += height / 2
+		}
+
+		// Self loop
+		if e.Source == e.Target {
+			fmt.Fprintf(&sb, "    <path class=\"edge\" data-id=\"%s\" d=\"M %d %d C %d %d %d %d %d %d\" fill=\"none\" stroke=\"#555555\" marker-end=\"url(#arrow)\"/>\n",
+				escape(e.ID), start[0]+width/4, start[1], start[0]+width/2+nodeSpacing, start[1]+layerSpacing/2, start[0]+width/2+nodeSpacing, end[1]-layerSpacing/2, end[0]+width/4, end[1],
+			)
+			continue
+		}
+
+		pts := make([]string, 0, len(points))
+		pts = append(pts, fmt.Sprintf("%d,%d", start[0], start[1]))
+		for _, p := range points[1 : len(points)-1] {
+			pts = append(pts, fmt.Sprintf("%d,%d", p[0], p[1]))
+		}
+		pts = append(pts, fmt.Sprintf("%d,%d", end[0], end[1]))
+
+		fmt.Fprintf(&sb, "    <polyline class=\"edge\" data-id=\"%s\" points=\"%s\" fill=\"none\" stroke=\"#555555\" marker-end=\"url(#arrow)\"/>\n", escape(e.ID), strings.Join(pts, " "))
+	}
+
+	for _, n := range g.Nodes {
+		// If it's nil the pv, it means we do not know it so we'll use
+		// the RawProvider.
+		pv, rs, _ := factory.GetProviderAndResource(n.Canonical)
+		if pv == nil {
+			pv = provider.RawProvider{}
+		}
+
+		x, y := n.Position[0], n.Position[1]
+		label := escape(labels[n.ID])
+
+		fmt.Fprintf(&sb, "    <g class=\"node\" data-id=\"%s\">\n      <title>%s</title>\n", escape(n.ID), escape(n.Canonical))
+		if opt.ShowIcons {
+			if n.Resource.Icon != "" {
+				icon, err := assets.IconBase64(pv.Type().String(), n.Resource.Icon)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(&sb, "      <image x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" href=\"data:image/png;base64,%s\"/>\n", x-iconSize/2, y-height/2, iconSize, iconSize, icon)
+			} else {
+				fmt.Fprintf(&sb, "      <rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"#ffffff\" stroke=\"#555555\"/>\n", x-iconSize/2, y-height/2, iconSize, iconSize)
+			}
+			fmt.Fprintf(&sb, "      <text x=\"%d\" y=\"%d\" text-anchor=\"middle\">%s</text>\n", x, y+height/2-lineHeight/4, label)
+		} else {
+			if pv.IsEdge(rs) {
+				fmt.Fprintf(&sb, "      <rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"#ffffff\" stroke=\"#555555\"/>\n", x-width/2, y-height/2, width, height)
+			} else {
+				fmt.Fprintf(&sb, "      <ellipse cx=\"%d\" cy=\"%d\" rx=\"%d\" ry=\"%d\" fill=\"#ffffff\" stroke=\"#555555\"/>\n", x, y, width/2, height/2)
+			}
+			fmt.Fprintf(&sb, "      <text x=\"%d\" y=\"%d\" text-anchor=\"middle\" dominant-baseline=\"middle\">%s</text>\n", x, y, label)
+		}
+		sb.WriteString("    </g>\n")
+	}
+
+	sb.WriteString("  </g>\n</svg>\n")
+
+	_, err = io.WriteString(w, sb.String())
+
+	return err
+}
+
+// escape escapes s to be used inside the SVG
+func escape(s string) string {
+	var sb strings.Builder
+	xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
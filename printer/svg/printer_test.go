@@ -0,0 +1,80 @@
+package svg_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/cycloidio/inframap/printer/svg"
+	"github.com/cycloidio/tfdocs/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type element struct {
+	Title string `xml:"title"`
+	Image *struct {
+		Href string `xml:"href,attr"`
+	} `xml:"image"`
+	Rect    *struct{} `xml:"rect"`
+	Ellipse *struct{} `xml:"ellipse"`
+	Text    string    `xml:"text"`
+}
+
+type file struct {
+	Polylines []struct {
+		Points string `xml:"points,attr"`
+	} `xml:"g>polyline"`
+	Nodes []element `xml:"g>g"`
+}
+
+func TestPrint(t *testing.T) {
+	g := graph.New()
+	n1 := &graph.Node{ID: "1", Canonical: "im_out.tcp/443->443", Resource: resource.Resource{Icon: "baseline_cloud_queue_black.svg"}}
+	n2 := &graph.Node{ID: "2", Canonical: "aws_security_group.front"}
+	n3 := &graph.Node{ID: "3", Canonical: "aws_instance.front"}
+
+	require.NoError(t, g.AddNode(n1))
+	require.NoError(t, g.AddNode(n2))
+	require.NoError(t, g.AddNode(n3))
+	require.NoError(t, g.AddEdge(&graph.Edge{ID: "1", Source: n1.ID, Target: n2.ID}))
+	require.NoError(t, g.AddEdge(&graph.Edge{ID: "2", Source: n2.ID, Target: n3.ID}))
+
+	t.Run("Success", func(t *testing.T) {
+		var buff bytes.Buffer
+		err := svg.SVG{}.Print(g, printer.Options{}, &buff)
+		require.NoError(t, err)
+
+		var f file
+		err = xml.Unmarshal(buff.Bytes(), &f)
+		require.NoError(t, err)
+
+		assert.Len(t, f.Polylines, 2)
+		require.Len(t, f.Nodes, 3)
+		assert.Equal(t, "im_out.tcp/443->443", f.Nodes[0].Title)
+		assert.NotNil(t, f.Nodes[0].Ellipse)
+		assert.NotNil(t, f.Nodes[1].Rect)
+		assert.NotNil(t, f.Nodes[2].Ellipse)
+
+		for _, n := range g.Nodes {
+			assert.Len(t, n.Position, 2)
+		}
+	})
+	t.Run("SuccessWithIcons", func(t *testing.T) {
+		var buff bytes.Buffer
+		err := svg.SVG{}.Print(g, printer.Options{ShowIcons: true}, &buff)
+		require.NoError(t, err)
+
+		var f file
+		err = xml.Unmarshal(buff.Bytes(), &f)
+		require.NoError(t, err)
+
+		require.Len(t, f.Nodes, 3)
+		require.NotNil(t, f.Nodes[0].Image)
+		assert.True(t, strings.HasPrefix(f.Nodes[0].Image.Href, "data:image/png;base64,"))
+		assert.Nil(t, f.Nodes[1].Image)
+	})
+}
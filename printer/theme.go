@@ -0,0 +1,155 @@
+package printer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cycloidio/inframap/errcode"
+	"gopkg.in/yaml.v3"
+)
+
+// List of the categories of the Edges
+// that can be styled with the Theme
+const (
+	// EdgeExternal are the edges from or
+	// to an external Node like 'im_out'
+	EdgeExternal = "external"
+
+	// EdgeConnection are the edges that have
+	// resources merged on them, like the
+	// security groups
+	EdgeConnection = "connection"
+
+	// EdgeDependency are the edges that
+	// are direct dependencies between Nodes
+	EdgeDependency = "dependency"
+)
+
+// Theme is the styling of the printed Graph, it can
+// be read from a YAML or JSON file with ParseTheme
+type Theme struct {
+	// RankDir is the direction of the Graph,
+	// ex: 'TB' (top to bottom) or 'LR' (left to right)
+	RankDir string `yaml:"rankdir"`
+
+	// Font is the name of the font used
+	// for all the texts
+	Font string `yaml:"font"`
+
+	// Node is the default style of the Nodes
+	Node NodeStyle `yaml:"node"`
+
+	// Providers is the style of the Nodes of each
+	// provider (ex: 'aws'), it's applied over the Node
+	Providers map[string]NodeStyle `yaml:"providers"`
+
+	// Resources is the style of the Nodes of each resource
+	// type (ex: 'aws_instance'), it's applied over the Providers
+	Resources map[string]NodeStyle `yaml:"resources"`
+
+	// Edges is the style of the edges of each category,
+	// the categories are EdgeExternal, EdgeConnection
+	// and EdgeDependency
+	Edges map[string]EdgeStyle `yaml:"edges"`
+
+	// Actions is the style of the Nodes of each action
+	// of a plan (ex: 'create'), it's applied over the
+	// Resources and the defaults of each printer
+	Actions map[string]NodeStyle `yaml:"actions"`
+
+	// Legend adds a legend with the styles
+	// of the Providers, Resources and Edges used
+	Legend bool `yaml:"legend"`
+}
+
+// NodeStyle is the style of a Node, the empty
+// values are not applied
+type NodeStyle struct {
+	Shape     string  `yaml:"shape"`
+	Color     string  `yaml:"color"`
+	FillColor string  `yaml:"fillcolor"`
+	FontColor string  `yaml:"fontcolor"`
+	Width     float64 `yaml:"width"`
+	Height    float64 `yaml:"height"`
+}
+
+// EdgeStyle is the style of an edge, the empty
+// values are not applied
+type EdgeStyle struct {
+	// Style is the line style,
+	// ex: 'solid', 'dashed' or 'dotted'
+	Style     string  `yaml:"style"`
+	Color     string  `yaml:"color"`
+	FontColor string  `yaml:"fontcolor"`
+	PenWidth  float64 `yaml:"penwidth"`
+}
+
+// rankDirs are the valid values of the Theme.RankDir
+var rankDirs = []string{"TB", "LR", "BT", "RL"}
+
+// ParseTheme parses the b, which can be YAML or JSON, into
+// a Theme, the unknown fields are not allowed so the typos
+// are not silently ignored
+func ParseTheme(b []byte) (*Theme, error) {
+	var t Theme
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(&t); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s: %w", err, errcode.ErrPrinterInvalidTheme)
+	}
+
+	if t.RankDir != "" {
+		var valid bool
+		for _, rd := range rankDirs {
+			if t.RankDir == rd {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("unknown rankdir %q, it has to be one of %s: %w", t.RankDir, strings.Join(rankDirs, ", "), errcode.ErrPrinterInvalidTheme)
+		}
+	}
+
+	for c := range t.Edges {
+		if c != EdgeExternal && c != EdgeConnection && c != EdgeDependency {
+			return nil, fmt.Errorf("unknown edge category %q: %w", c, errcode.ErrPrinterInvalidTheme)
+		}
+	}
+
+	return &t, nil
+}
+
+// Merge returns the ns with the non empty
+// values of o applied over it
+func (ns NodeStyle) Merge(o NodeStyle) NodeStyle {
+	if o.Shape != "" {
+		ns.Shape = o.Shape
+	}
+	if o.Color != "" {
+		ns.Color = o.Color
+	}
+	if o.FillColor != "" {
+		ns.FillColor = o.FillColor
+	}
+	if o.FontColor != "" {
+		ns.FontColor = o.FontColor
+	}
+	if o.Width != 0 {
+		ns.Width = o.Width
+	}
+	if o.Height != 0 {
+		ns.Height = o.Height
+	}
+	return ns
+}
+
+// NodeStyle returns the style of the Node of the
+// provider pv and resource type rs, applying the
+// Node, then the Providers and then the Resources
+func (t *Theme) NodeStyle(pv, rs string) NodeStyle {
+	return t.Node.Merge(t.Providers[pv]).Merge(t.Resources[rs])
+}
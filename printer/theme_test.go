@@ -0,0 +1,75 @@
+package printer_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cycloidio/inframap/errcode"
+	"github.com/cycloidio/inframap/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTheme(t *testing.T) {
+	t.Run("SuccessYAML", func(t *testing.T) {
+		th, err := printer.ParseTheme([]byte(`
+rankdir: LR
+font: Helvetica
+node:
+  shape: box
+providers:
+  aws:
+    fillcolor: "#ff9900"
+resources:
+  aws_db_instance:
+    shape: cylinder
+    width: 1.5
+edges:
+  external:
+    style: dashed
+actions:
+  create:
+    fillcolor: green
+legend: true
+`))
+		require.NoError(t, err)
+		assert.Equal(t, &printer.Theme{
+			RankDir:   "LR",
+			Font:      "Helvetica",
+			Node:      printer.NodeStyle{Shape: "box"},
+			Providers: map[string]printer.NodeStyle{"aws": {FillColor: "#ff9900"}},
+			Resources: map[string]printer.NodeStyle{"aws_db_instance": {Shape: "cylinder", Width: 1.5}},
+			Edges:     map[string]printer.EdgeStyle{printer.EdgeExternal: {Style: "dashed"}},
+			Actions:   map[string]printer.NodeStyle{"create": {FillColor: "green"}},
+			Legend:    true,
+		}, th)
+
+		assert.Equal(t, printer.NodeStyle{Shape: "cylinder", FillColor: "#ff9900", Width: 1.5}, th.NodeStyle("aws", "aws_db_instance"))
+		assert.Equal(t, printer.NodeStyle{Shape: "box", FillColor: "#ff9900"}, th.NodeStyle("aws", "aws_instance"))
+		assert.Equal(t, printer.NodeStyle{Shape: "box"}, th.NodeStyle("google", "google_compute_instance"))
+	})
+	t.Run("SuccessJSON", func(t *testing.T) {
+		th, err := printer.ParseTheme([]byte(`{"rankdir":"TB","edges":{"connection":{"color":"red","penwidth":2}}}`))
+		require.NoError(t, err)
+		assert.Equal(t, &printer.Theme{
+			RankDir: "TB",
+			Edges:   map[string]printer.EdgeStyle{printer.EdgeConnection: {Color: "red", PenWidth: 2}},
+		}, th)
+	})
+	t.Run("ErrorUnknownEdgeCategory", func(t *testing.T) {
+		_, err := printer.ParseTheme([]byte(`{"edges":{"potato":{"color":"red"}}}`))
+		assert.True(t, errors.Is(err, errcode.ErrPrinterInvalidTheme))
+	})
+	t.Run("ErrorUnknownField", func(t *testing.T) {
+		_, err := printer.ParseTheme([]byte(`{"rankdir":"LR","node":{"colour":"red"}}`))
+		assert.True(t, errors.Is(err, errcode.ErrPrinterInvalidTheme))
+	})
+	t.Run("ErrorUnknownRankDir", func(t *testing.T) {
+		_, err := printer.ParseTheme([]byte(`rankdir: left`))
+		assert.True(t, errors.Is(err, errcode.ErrPrinterInvalidTheme))
+	})
+	t.Run("ErrorInvalidFormat", func(t *testing.T) {
+		_, err := printer.ParseTheme([]byte(`rankdir: [`))
+		assert.True(t, errors.Is(err, errcode.ErrPrinterInvalidTheme))
+	})
+}
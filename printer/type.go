@@ -8,4 +8,17 @@ type Type int
 // List of all Types
 const (
 	DOT Type = iota
+	JSON
+	Mermaid
+	DrawIO
+	SVG
+	HTML
+	ASCII
+	GraphML
+	GEXF
+	PlantUML
+	D2
+	Cytoscape
+	Structurizr
+	Cypher
 )
@@ -7,11 +7,11 @@ import (
 	"strings"
 )
 
-const _TypeName = "dot"
+const _TypeName = "dotjsonmermaiddrawiosvghtmlasciigraphmlgexfplantumld2cytoscapestructurizrcypher"
 
-var _TypeIndex = [...]uint8{0, 3}
+var _TypeIndex = [...]uint8{0, 3, 7, 14, 20, 23, 27, 32, 39, 43, 51, 53, 62, 73, 79}
 
-const _TypeLowerName = "dot"
+const _TypeLowerName = "dotjsonmermaiddrawiosvghtmlasciigraphmlgexfplantumld2cytoscapestructurizrcypher"
 
 func (i Type) String() string {
 	if i < 0 || i >= Type(len(_TypeIndex)-1) {
@@ -25,17 +25,69 @@ func (i Type) String() string {
 func _TypeNoOp() {
 	var x [1]struct{}
 	_ = x[DOT-(0)]
+	_ = x[JSON-(1)]
+	_ = x[Mermaid-(2)]
+	_ = x[DrawIO-(3)]
+	_ = x[SVG-(4)]
+	_ = x[HTML-(5)]
+	_ = x[ASCII-(6)]
+	_ = x[GraphML-(7)]
+	_ = x[GEXF-(8)]
+	_ = x[PlantUML-(9)]
+	_ = x[D2-(10)]
+	_ = x[Cytoscape-(11)]
+	_ = x[Structurizr-(12)]
+	_ = x[Cypher-(13)]
 }
 
-var _TypeValues = []Type{DOT}
+var _TypeValues = []Type{DOT, JSON, Mermaid, DrawIO, SVG, HTML, ASCII, GraphML, GEXF, PlantUML, D2, Cytoscape, Structurizr, Cypher}
 
 var _TypeNameToValueMap = map[string]Type{
-	_TypeName[0:3]:      DOT,
-	_TypeLowerName[0:3]: DOT,
+	_TypeName[0:3]:        DOT,
+	_TypeLowerName[0:3]:   DOT,
+	_TypeName[3:7]:        JSON,
+	_TypeLowerName[3:7]:   JSON,
+	_TypeName[7:14]:       Mermaid,
+	_TypeLowerName[7:14]:  Mermaid,
+	_TypeName[14:20]:      DrawIO,
+	_TypeLowerName[14:20]: DrawIO,
+	_TypeName[20:23]:      SVG,
+	_TypeLowerName[20:23]: SVG,
+	_TypeName[23:27]:      HTML,
+	_TypeLowerName[23:27]: HTML,
+	_TypeName[27:32]:      ASCII,
+	_TypeLowerName[27:32]: ASCII,
+	_TypeName[32:39]:      GraphML,
+	_TypeLowerName[32:39]: GraphML,
+	_TypeName[39:43]:      GEXF,
+	_TypeLowerName[39:43]: GEXF,
+	_TypeName[43:51]:      PlantUML,
+	_TypeLowerName[43:51]: PlantUML,
+	_TypeName[51:53]:      D2,
+	_TypeLowerName[51:53]: D2,
+	_TypeName[53:62]:      Cytoscape,
+	_TypeLowerName[53:62]: Cytoscape,
+	_TypeName[62:73]:      Structurizr,
+	_TypeLowerName[62:73]: Structurizr,
+	_TypeName[73:79]:      Cypher,
+	_TypeLowerName[73:79]: Cypher,
 }
 
 var _TypeNames = []string{
 	_TypeName[0:3],
+	_TypeName[3:7],
+	_TypeName[7:14],
+	_TypeName[14:20],
+	_TypeName[20:23],
+	_TypeName[23:27],
+	_TypeName[27:32],
+	_TypeName[32:39],
+	_TypeName[39:43],
+	_TypeName[43:51],
+	_TypeName[51:53],
+	_TypeName[53:62],
+	_TypeName[62:73],
+	_TypeName[73:79],
 }
 
 // TypeString retrieves an enum value from the enum constants string name.
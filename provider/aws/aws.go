@@ -2,8 +2,10 @@ package aws
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/cycloidio/inframap/errcode"
+	"github.com/cycloidio/inframap/graph"
 	"github.com/cycloidio/inframap/provider"
 
 	tfdocAWS "github.com/cycloidio/tfdocs/providers/aws"
@@ -22,6 +24,23 @@ var (
 		"ingress",
 		"source_security_group_id",
 		"security_group_id",
+		"vpc_id",
+		"subnet_id",
+		"vpc_security_group_ids",
+		"security_groups",
+		"security_group_ids",
+		"type",
+		"protocol",
+		"from_port",
+		"to_port",
+	}
+
+	// securityGroupsAttributes are the attributes that
+	// hold the security groups of a resource
+	securityGroupsAttributes = []string{
+		"vpc_security_group_ids",
+		"security_groups",
+		"security_group_ids",
 	}
 )
 
@@ -115,3 +134,137 @@ func (a Provider) ResourceInOutNodes(id, rs string, cfgs map[string]map[string]i
 func (a Provider) UsedAttributes() []string {
 	return usedAttributes
 }
+
+// ResourceGroups returns the VPC and the subnet in which the resource
+// with the id is. If the resource does not have the 'vpc_id' it's
+// taken from the security groups it has, as they are always on a VPC
+func (a Provider) ResourceGroups(id, rs string, cfgs map[string]map[string]interface{}) []provider.Group {
+	var groups []provider.Group
+	cfg := cfgs[id]
+
+	vpc, _ := cfg["vpc_id"].(string)
+	if vpc == "" {
+	SG:
+		for _, attr := range securityGroupsAttributes {
+			sgs, _ := cfg[attr].([]interface{})
+			for _, sg := range sgs {
+				sgid, _ := sg.(string)
+				if vpc = securityGroupVPC(sgid, cfgs); vpc != "" {
+					break SG
+				}
+			}
+		}
+	}
+
+	if vpc != "" {
+		groups = append(groups, provider.Group{ID: vpc, Type: "aws_vpc"})
+	}
+
+	if subnet, _ := cfg["subnet_id"].(string); subnet != "" {
+		groups = append(groups, provider.Group{ID: subnet, Type: "aws_subnet"})
+	}
+
+	return groups
+}
+
+// securityGroupVPC returns the 'vpc_id' of the security group
+// with the sgid from the cfgs. The sgid could be the ID of
+// the security group or, from HCL, the reference to it
+// like '${aws_security_group.front.id}'
+func securityGroupVPC(sgid string, cfgs map[string]map[string]interface{}) string {
+	if sgid == "" {
+		return ""
+	}
+
+	for _, cfg := range cfgs {
+		id, _ := cfg["id"].(string)
+		can, _ := cfg[provider.HCLCanonicalKey].(string)
+		if id == sgid || (can != "" && fmt.Sprintf("${%s.id}", can) == sgid) {
+			vpc, _ := cfg["vpc_id"].(string)
+			return vpc
+		}
+	}
+
+	return ""
+}
+
+// ResourceRules returns the ports of each ingress rule of
+// the security groups with the sources it allows them from
+func (a Provider) ResourceRules(id, rs string, cfgs map[string]map[string]interface{}) []provider.Rule {
+	var rules []provider.Rule
+	cfg := cfgs[id]
+	switch rs {
+	case "aws_security_group":
+		ingress, _ := cfg["ingress"].([]interface{})
+		for _, in := range ingress {
+			min, _ := in.(map[string]interface{})
+			if p, ok := ruleToPort(min); ok {
+				rules = append(rules, provider.Rule{
+					Sources: ruleSources(min, "security_groups"),
+					Port:    p,
+				})
+			}
+		}
+	case "aws_security_group_rule":
+		if t, _ := cfg["type"].(string); t == "ingress" {
+			if p, ok := ruleToPort(cfg); ok {
+				rules = append(rules, provider.Rule{
+					Sources: ruleSources(cfg, "source_security_group_id"),
+					Port:    p,
+				})
+			}
+		}
+	}
+
+	return rules
+}
+
+// ruleSources returns the security groups of the sgKey
+// and the CIDRs from which the rule allows the traffic
+func ruleSources(rule map[string]interface{}, sgKey string) []string {
+	var sources []string
+	for _, k := range []string{sgKey, "cidr_blocks", "ipv6_cidr_blocks"} {
+		switch v := rule[k].(type) {
+		case string:
+			sources = append(sources, v)
+		case []interface{}:
+			for _, s := range v {
+				if ss, ok := s.(string); ok {
+					sources = append(sources, ss)
+				}
+			}
+		}
+	}
+	return sources
+}
+
+// ruleToPort returns the graph.Port from the 'protocol',
+// 'from_port' and 'to_port' of the rule
+func ruleToPort(rule map[string]interface{}) (graph.Port, bool) {
+	protocol, ok := rule["protocol"].(string)
+	if !ok || protocol == "" {
+		return graph.Port{}, false
+	}
+
+	return graph.Port{
+		Protocol: protocol,
+		FromPort: toInt(rule["from_port"]),
+		ToPort:   toInt(rule["to_port"]),
+	}, true
+}
+
+// toInt converts the v to int, as it could come
+// from JSON (float64), from HCL (int) or
+// from TF 0.11 flatmap (string)
+func toInt(v interface{}) int {
+	switch vv := v.(type) {
+	case float64:
+		return int(vv)
+	case int:
+		return vv
+	case string:
+		i, _ := strconv.Atoi(vv)
+		return i
+	}
+	return 0
+}
@@ -3,6 +3,8 @@ package aws_test
 import (
 	"testing"
 
+	"github.com/cycloidio/inframap/graph"
+	"github.com/cycloidio/inframap/provider"
 	"github.com/cycloidio/inframap/provider/aws"
 	"github.com/stretchr/testify/assert"
 )
@@ -51,3 +53,119 @@ func TestResourceInOutNodes(t *testing.T) {
 		assert.Equal(t, []string{"out-id"}, outs)
 	})
 }
+
+func TestResourceGroups(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		aws := aws.Provider{}
+		id := "id"
+		rs := "aws_instance"
+		cfg := map[string]map[string]interface{}{
+			id: {
+				"subnet_id": "subnet-id",
+				"vpc_security_group_ids": []interface{}{
+					"sg-id",
+				},
+			},
+			"sg": {
+				"id":     "sg-id",
+				"vpc_id": "vpc-id",
+			},
+		}
+
+		groups := aws.ResourceGroups(id, rs, cfg)
+		assert.Equal(t, []provider.Group{
+			{ID: "vpc-id", Type: "aws_vpc"},
+			{ID: "subnet-id", Type: "aws_subnet"},
+		}, groups)
+	})
+	t.Run("SuccessWithHCLCanonicalKey", func(t *testing.T) {
+		aws := aws.Provider{}
+		id := "id"
+		rs := "aws_db_instance"
+		cfg := map[string]map[string]interface{}{
+			id: {
+				"vpc_security_group_ids": []interface{}{
+					"${aws_security_group.db.id}",
+				},
+			},
+			"sg": {
+				provider.HCLCanonicalKey: "aws_security_group.db",
+				"vpc_id":                 "${aws_vpc.main.id}",
+			},
+		}
+
+		groups := aws.ResourceGroups(id, rs, cfg)
+		assert.Equal(t, []provider.Group{
+			{ID: "${aws_vpc.main.id}", Type: "aws_vpc"},
+		}, groups)
+	})
+	t.Run("SuccessWithoutGroups", func(t *testing.T) {
+		aws := aws.Provider{}
+		id := "id"
+		rs := "aws_s3_bucket"
+		cfg := map[string]map[string]interface{}{
+			id: {},
+		}
+
+		groups := aws.ResourceGroups(id, rs, cfg)
+		assert.Nil(t, groups)
+	})
+}
+
+func TestResourceRules(t *testing.T) {
+	t.Run("SuccessSG", func(t *testing.T) {
+		aws := aws.Provider{}
+		id := "id"
+		rs := "aws_security_group"
+		cfg := map[string]map[string]interface{}{
+			id: {
+				"ingress": []interface{}{
+					map[string]interface{}{
+						"protocol":    "tcp",
+						"from_port":   float64(443),
+						"to_port":     float64(443),
+						"cidr_blocks": []interface{}{"0.0.0.0/0"},
+					},
+					map[string]interface{}{
+						"protocol":        "tcp",
+						"from_port":       8000,
+						"to_port":         8080,
+						"security_groups": []interface{}{"sg-bastion"},
+					},
+				},
+				"egress": []interface{}{
+					map[string]interface{}{
+						"protocol":  "-1",
+						"from_port": float64(0),
+						"to_port":   float64(0),
+					},
+				},
+			},
+		}
+
+		rules := aws.ResourceRules(id, rs, cfg)
+		assert.Equal(t, []provider.Rule{
+			{Sources: []string{"0.0.0.0/0"}, Port: graph.Port{Protocol: "tcp", FromPort: 443, ToPort: 443}},
+			{Sources: []string{"sg-bastion"}, Port: graph.Port{Protocol: "tcp", FromPort: 8000, ToPort: 8080}},
+		}, rules)
+	})
+	t.Run("SuccessSGR", func(t *testing.T) {
+		aws := aws.Provider{}
+		id := "id"
+		rs := "aws_security_group_rule"
+		cfg := map[string]map[string]interface{}{
+			id: {
+				"type":                     "ingress",
+				"protocol":                 "tcp",
+				"from_port":                "22",
+				"to_port":                  "22",
+				"source_security_group_id": "sg-bastion",
+			},
+		}
+
+		rules := aws.ResourceRules(id, rs, cfg)
+		assert.Equal(t, []provider.Rule{
+			{Sources: []string{"sg-bastion"}, Port: graph.Port{Protocol: "tcp", FromPort: 22, ToPort: 22}},
+		}, rules)
+	})
+}
@@ -2,6 +2,7 @@ package azurerm
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/cycloidio/inframap/errcode"
 	"github.com/cycloidio/inframap/provider"
@@ -86,6 +87,9 @@ var (
 		"virtual_network_name",
 		"id",
 		"name",
+		"resource_group_name",
+		"subnet_id",
+		"ip_configuration",
 	}
 )
 
@@ -95,3 +99,54 @@ var (
 func (a Provider) UsedAttributes() []string {
 	return usedAttributes
 }
+
+// ResourceGroups returns the resource group and the virtual network
+// in which the resource with the id is. The virtual network could
+// also be taken from the subnet ID
+func (a Provider) ResourceGroups(id, rs string, cfgs map[string]map[string]interface{}) []provider.Group {
+	var groups []provider.Group
+	cfg := cfgs[id]
+
+	rg, _ := cfg["resource_group_name"].(string)
+	if rg != "" {
+		groups = append(groups, provider.Group{ID: rg, Type: "azurerm_resource_group"})
+	}
+
+	vnet, _ := cfg["virtual_network_name"].(string)
+	if vnet == "" {
+		snids := make([]string, 0)
+		if snid, ok := cfg["subnet_id"].(string); ok {
+			snids = append(snids, snid)
+		}
+		ipcs, _ := cfg["ip_configuration"].([]interface{})
+		for _, ipc := range ipcs {
+			mipc, _ := ipc.(map[string]interface{})
+			if snid, ok := mipc["subnet_id"].(string); ok {
+				snids = append(snids, snid)
+			}
+		}
+		for _, snid := range snids {
+			if vnet = subnetVNet(snid); vnet != "" {
+				break
+			}
+		}
+	}
+
+	if vnet != "" {
+		groups = append(groups, provider.Group{ID: vnet, Type: "azurerm_virtual_network"})
+	}
+
+	return groups
+}
+
+// subnetVNet returns the name of the virtual network from the
+// subnet ID which is like '.../virtualNetworks/NAME/subnets/NAME'
+func subnetVNet(snid string) string {
+	parts := strings.Split(snid, "/")
+	for i, p := range parts {
+		if strings.EqualFold(p, "virtualNetworks") && i+1 < len(parts) {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
@@ -3,6 +3,7 @@ package azurerm_test
 import (
 	"testing"
 
+	"github.com/cycloidio/inframap/provider"
 	"github.com/cycloidio/inframap/provider/azurerm"
 	"github.com/stretchr/testify/assert"
 )
@@ -28,3 +29,27 @@ func TestResourceInOut(t *testing.T) {
 		assert.Equal(t, []string{"remote_v_network"}, outs)
 	})
 }
+
+func TestResourceGroups(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		azure := azurerm.Provider{}
+		id := "id"
+		rs := "azurerm_network_interface"
+		cfg := map[string]map[string]interface{}{
+			id: {
+				"resource_group_name": "rg",
+				"ip_configuration": []interface{}{
+					map[string]interface{}{
+						"subnet_id": "/subscriptions/sid/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet/subnets/internal",
+					},
+				},
+			},
+		}
+
+		groups := azure.ResourceGroups(id, rs, cfg)
+		assert.Equal(t, []provider.Group{
+			{ID: "rg", Type: "azurerm_resource_group"},
+			{ID: "vnet", Type: "azurerm_virtual_network"},
+		}, groups)
+	})
+}
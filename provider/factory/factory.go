@@ -28,14 +28,19 @@ var (
 
 	// reProvider is a regexp to match 'aws' from 'aws' or 'aws_iam_user'
 	reProvider = regexp.MustCompile(`(?P<provider>[^_][a-z0-9]+)(?:_+)?`)
+
+	// reIndex matches the keys of the instances
+	// like '[0]' or '["key"]'
+	reIndex = regexp.MustCompile(`\[[^\]]*\]`)
 )
 
 // GetProviderAndResource returns the Interface
 // and the resource name "aws_alb.front" -> "aws_alb"
 func GetProviderAndResource(can string) (provider.Provider, string, error) {
 	// Due to modules, we'll check it from the back not from
-	// the front as it may have modules prefix
-	rss := strings.Split(can, ".")
+	// the front as it may have modules prefix, and without the
+	// keys of the instances as they could have dots
+	rss := strings.Split(reIndex.ReplaceAllString(can, ""), ".")
 	var rs string
 	if len(rss) > 1 {
 		rs = rss[len(rss)-2]
@@ -2,8 +2,12 @@ package google
 
 import (
 	"fmt"
+	"path"
+	"strconv"
+	"strings"
 
 	"github.com/cycloidio/inframap/errcode"
+	"github.com/cycloidio/inframap/graph"
 	"github.com/cycloidio/inframap/provider"
 
 	tfdocGCP "github.com/cycloidio/tfdocs/providers/google"
@@ -22,6 +26,12 @@ var (
 		"target_tags",
 		"source_tags",
 		"tags",
+		"network",
+		"network_interface",
+		"region",
+		"zone",
+		"location",
+		"allow",
 	}
 )
 
@@ -207,3 +217,113 @@ func (a Provider) PreProcess(cfg map[string]map[string]interface{}) [][]string {
 func (a Provider) UsedAttributes() []string {
 	return usedAttributes
 }
+
+// ResourceGroups returns the network and the region in which the
+// resource with the id is. The region could also be taken from
+// the 'zone' or the 'location'
+func (a Provider) ResourceGroups(id, rs string, cfgs map[string]map[string]interface{}) []provider.Group {
+	var groups []provider.Group
+	cfg := cfgs[id]
+
+	network, _ := cfg["network"].(string)
+	if network == "" {
+		nis, _ := cfg["network_interface"].([]interface{})
+		for _, ni := range nis {
+			mni, _ := ni.(map[string]interface{})
+			if network, _ = mni["network"].(string); network != "" {
+				break
+			}
+		}
+	}
+
+	if network != "" {
+		// The network can be the self link
+		// so we only need the name
+		groups = append(groups, provider.Group{ID: path.Base(network), Type: "google_compute_network"})
+	}
+
+	region, _ := cfg["region"].(string)
+	if region == "" {
+		region, _ = cfg["zone"].(string)
+		if region == "" {
+			region, _ = cfg["location"].(string)
+		}
+		// The zones are 'REGION-a' so we remove
+		// the last part to have the region
+		if parts := strings.Split(path.Base(region), "-"); len(parts) == 3 {
+			region = strings.Join(parts[:2], "-")
+		}
+	}
+
+	if region != "" {
+		// The region can be the self link
+		// so we only need the name
+		groups = append(groups, provider.Group{ID: path.Base(region), Type: "region"})
+	}
+
+	return groups
+}
+
+// ResourceRules returns the ports of the 'allow' rules of the
+// firewalls, if no ports are defined it means all of them so only
+// the protocol is set. All of them are allowed from the same
+// sources, the resources with the 'source_tags' and the 'source_ranges'
+func (a Provider) ResourceRules(id, rs string, cfgs map[string]map[string]interface{}) []provider.Rule {
+	if rs != "google_compute_firewall" {
+		return nil
+	}
+
+	var sources []string
+	if direction, _ := cfgs[id]["direction"].(string); direction == "INGRESS" {
+		sources, _, _ = a.ResourceInOutNodes(id, rs, cfgs)
+	}
+	ranges, _ := cfgs[id]["source_ranges"].([]interface{})
+	for _, r := range ranges {
+		if sr, ok := r.(string); ok {
+			sources = append(sources, sr)
+		}
+	}
+
+	var ports []graph.Port
+	allow, _ := cfgs[id]["allow"].([]interface{})
+	for _, al := range allow {
+		mal, _ := al.(map[string]interface{})
+		protocol, _ := mal["protocol"].(string)
+		if protocol == "" {
+			continue
+		}
+
+		pts, _ := mal["ports"].([]interface{})
+		if len(pts) == 0 {
+			ports = append(ports, graph.Port{Protocol: protocol})
+			continue
+		}
+
+		for _, pt := range pts {
+			// The ports are '80' or '8000-8080'
+			spt := fmt.Sprintf("%v", pt)
+			from, to := spt, spt
+			if i := strings.Index(spt, "-"); i != -1 {
+				from, to = spt[:i], spt[i+1:]
+			}
+
+			fp, err := strconv.Atoi(from)
+			if err != nil {
+				continue
+			}
+			tp, err := strconv.Atoi(to)
+			if err != nil {
+				continue
+			}
+
+			ports = append(ports, graph.Port{Protocol: protocol, FromPort: fp, ToPort: tp})
+		}
+	}
+
+	rules := make([]provider.Rule, 0, len(ports))
+	for _, p := range ports {
+		rules = append(rules, provider.Rule{Sources: sources, Port: p})
+	}
+
+	return rules
+}
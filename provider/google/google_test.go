@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/cycloidio/inframap/graph"
 	"github.com/cycloidio/inframap/provider"
 	"github.com/cycloidio/inframap/provider/google"
 	"github.com/stretchr/testify/assert"
@@ -133,3 +134,72 @@ func TestResourceInOutNodes(t *testing.T) {
 		assert.Equal(t, []string(nil), outs)
 	})
 }
+
+func TestResourceGroups(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		google := google.Provider{}
+		id := "id"
+		rs := "google_compute_instance"
+		cfg := map[string]map[string]interface{}{
+			id: {
+				"zone": "europe-west1-b",
+				"network_interface": []interface{}{
+					map[string]interface{}{
+						"network": "https://www.googleapis.com/compute/v1/projects/project/global/networks/default",
+					},
+				},
+			},
+		}
+
+		groups := google.ResourceGroups(id, rs, cfg)
+		assert.Equal(t, []provider.Group{
+			{ID: "default", Type: "google_compute_network"},
+			{ID: "europe-west1", Type: "region"},
+		}, groups)
+	})
+	t.Run("SuccessRegion", func(t *testing.T) {
+		google := google.Provider{}
+		id := "id"
+		rs := "google_sql_database_instance"
+		cfg := map[string]map[string]interface{}{
+			id: {
+				"region": "europe-west1",
+			},
+		}
+
+		groups := google.ResourceGroups(id, rs, cfg)
+		assert.Equal(t, []provider.Group{
+			{ID: "europe-west1", Type: "region"},
+		}, groups)
+	})
+}
+
+func TestResourceRules(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		google := google.Provider{}
+		id := "id"
+		rs := "google_compute_firewall"
+		cfg := map[string]map[string]interface{}{
+			id: {
+				"direction":     "INGRESS",
+				"source_ranges": []interface{}{"10.0.0.0/8"},
+				"allow": []interface{}{
+					map[string]interface{}{
+						"protocol": "tcp",
+						"ports":    []interface{}{"22", "8000-8080"},
+					},
+					map[string]interface{}{
+						"protocol": "icmp",
+					},
+				},
+			},
+		}
+
+		rules := google.ResourceRules(id, rs, cfg)
+		assert.Equal(t, []provider.Rule{
+			{Sources: []string{"10.0.0.0/8"}, Port: graph.Port{Protocol: "tcp", FromPort: 22, ToPort: 22}},
+			{Sources: []string{"10.0.0.0/8"}, Port: graph.Port{Protocol: "tcp", FromPort: 8000, ToPort: 8080}},
+			{Sources: []string{"10.0.0.0/8"}, Port: graph.Port{Protocol: "icmp"}},
+		}, rules)
+	})
+}
@@ -50,3 +50,16 @@ func (n NopProvider) UsedAttributes() []string { return nil }
 func (n NopProvider) PreProcess(cfg map[string]map[string]interface{}) [][]string {
 	return nil
 }
+
+// ResourceGroups returns the Groups in which the resource
+// with the id is based on the cfg, from the outermost to the
+// innermost
+func (n NopProvider) ResourceGroups(id, rs string, cfg map[string]map[string]interface{}) []Group {
+	return nil
+}
+
+// ResourceRules returns the traffic that the resource with
+// the id allows, and from where, based on the cfg
+func (n NopProvider) ResourceRules(id, rs string, cfg map[string]map[string]interface{}) []Rule {
+	return nil
+}
@@ -25,6 +25,9 @@ var (
 		"loadbalancer_id",
 		"listener_id",
 		"pool_id",
+		"network_id",
+		"vip_network_id",
+		"network",
 	}
 )
 
@@ -110,3 +113,33 @@ func (a Provider) ResourceInOutNodes(id, rs string, cfgs map[string]map[string]i
 func (a Provider) UsedAttributes() []string {
 	return usedAttributes
 }
+
+// ResourceGroups returns the network in which the resource with the id
+// is. If the resource is attached to more than one network
+// the first one is used
+func (a Provider) ResourceGroups(id, rs string, cfgs map[string]map[string]interface{}) []provider.Group {
+	cfg := cfgs[id]
+
+	network, _ := cfg["network_id"].(string)
+	if network == "" {
+		network, _ = cfg["vip_network_id"].(string)
+	}
+	if network == "" {
+		nets, _ := cfg["network"].([]interface{})
+		for _, n := range nets {
+			mn, _ := n.(map[string]interface{})
+			if network, _ = mn["uuid"].(string); network != "" {
+				break
+			}
+			if network, _ = mn["name"].(string); network != "" {
+				break
+			}
+		}
+	}
+
+	if network == "" {
+		return nil
+	}
+
+	return []provider.Group{{ID: network, Type: "openstack_networking_network_v2"}}
+}
@@ -3,6 +3,7 @@ package openstack_test
 import (
 	"testing"
 
+	"github.com/cycloidio/inframap/provider"
 	"github.com/cycloidio/inframap/provider/openstack"
 	"github.com/stretchr/testify/assert"
 )
@@ -111,3 +112,26 @@ func TestResourceInOutNodes(t *testing.T) {
 		assert.Equal(t, []string(nil), outs)
 	})
 }
+
+func TestResourceGroups(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		os := openstack.Provider{}
+		id := "id"
+		rs := "openstack_compute_instance_v2"
+		cfg := map[string]map[string]interface{}{
+			id: {
+				"network": []interface{}{
+					map[string]interface{}{
+						"uuid": "network-id",
+						"name": "network-name",
+					},
+				},
+			},
+		}
+
+		groups := os.ResourceGroups(id, rs, cfg)
+		assert.Equal(t, []provider.Group{
+			{ID: "network-id", Type: "openstack_networking_network_v2"},
+		}, groups)
+	})
+}
@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"github.com/cycloidio/inframap/graph"
 	"github.com/cycloidio/tfdocs/resource"
 )
 
@@ -48,4 +49,39 @@ type Provider interface {
 	// [_][0] is the source of the edge
 	// [_][1] is the target of the edge
 	PreProcess(cfg map[string]map[string]interface{}) [][]string
+
+	// ResourceGroups returns the Groups in which the resource
+	// with the id is based on the cfg, from the outermost to the
+	// innermost. As an example in AWS it would be the VPC and
+	// then the subnet
+	ResourceGroups(id, rs string, cfg map[string]map[string]interface{}) []Group
+
+	// ResourceRules returns the traffic that the resource with
+	// the id allows, and from where, based on the cfg, it's only
+	// relevant for the resources that are Edges. As an example in
+	// AWS this would be the "ingress" of an "aws_security_group"
+	ResourceRules(id, rs string, cfg map[string]map[string]interface{}) []Rule
+}
+
+// Group is a container of resources on the
+// Provider, like a VPC, a subnet or a region
+type Group struct {
+	// ID is the identifier of the Group on
+	// the Provider, ex: 'vpc-0a1b2c'
+	ID string
+
+	// Type is the kind of Group, ex: 'aws_vpc'
+	Type string
+}
+
+// Rule is the traffic that a resource that
+// is an Edge allows from some sources
+type Rule struct {
+	// Sources are from where the traffic is allowed, the IDs
+	// or the references ('${aws_security_group.front.id}')
+	// of the resources or the CIDRs
+	Sources []string
+
+	// Port is the traffic allowed
+	Port graph.Port
 }
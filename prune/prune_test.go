@@ -0,0 +1,43 @@
+package prune_test
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/cycloidio/inframap/prune"
+	"github.com/cycloidio/inframap/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrune(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/state.json")
+		require.NoError(t, err)
+		epruned, err := ioutil.ReadFile("./testdata/state_pruned.json")
+		require.NoError(t, err)
+
+		pruned, err := prune.Prune(src, false)
+		require.NoError(t, err)
+
+		assert.JSONEq(t, string(epruned), string(pruned))
+	})
+	t.Run("SuccessReplaceCanonicals", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/state.json")
+		require.NoError(t, err)
+
+		pruned, err := prune.Prune(src, true)
+		require.NoError(t, err)
+
+		s, err := state.Read(pruned)
+		require.NoError(t, err)
+		require.Len(t, s.Resources, 2)
+
+		sg, web := s.Resources[0], s.Resources[1]
+		assert.NotEqual(t, "front", sg.Name)
+		assert.NotEqual(t, "web", web.Name)
+		for _, i := range web.Instances {
+			assert.Equal(t, []string{sg.Address()}, i.Dependencies)
+		}
+	})
+}
@@ -0,0 +1,243 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cycloidio/inframap/errcode"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/spf13/afero"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// List of the Types of Backend that
+// are supported by default
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+)
+
+// DefaultWorkspace is the workspace used
+// when none has been selected
+const DefaultWorkspace = "default"
+
+// Backend is where the TFState of a
+// Terraform working directory is stored
+type Backend struct {
+	// Type it's the type of the backend, like 'local' or 's3'
+	Type string `json:"type"`
+
+	// Config it's the configuration of the backend, the
+	// attributes of the 'backend' block like 'bucket'
+	Config map[string]interface{} `json:"config"`
+}
+
+// Reader reads the TFState of the workspace from
+// a Backend that is not on the working directory
+type Reader interface {
+	Read(b Backend, workspace string) ([]byte, error)
+}
+
+// String returns the value of the key on the
+// Config, empty if it's not set or not a string
+func (b Backend) String(key string) string {
+	s, _ := b.Config[key].(string)
+	return s
+}
+
+// Bool returns the value of the key on the
+// Config, false if it's not set or not a bool
+func (b Backend) Bool(key string) bool {
+	v, _ := b.Config[key].(bool)
+	return v
+}
+
+// ReadDir reads the TFState of the workspace of the Terraform working dir, if the
+// workspace is empty the one selected on the dir is used. The Backend is the one
+// initialized on '.terraform/terraform.tfstate', if not the 'backend' block of the
+// configuration or the local one if none. The TFStates that are not on the dir are
+// read with the readers, the key is the Backend.Type
+func ReadDir(fs afero.Fs, dir, workspace string, readers map[string]Reader) ([]byte, error) {
+	if workspace == "" {
+		b, err := afero.ReadFile(fs, filepath.Join(dir, ".terraform", "environment"))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		workspace = strings.TrimSpace(string(b))
+		if workspace == "" {
+			workspace = DefaultWorkspace
+		}
+	}
+
+	b, err := readBackend(fs, dir)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading the backend: %w", err)
+	}
+
+	if b.Type == BackendLocal {
+		return readLocal(fs, dir, b, workspace)
+	}
+
+	r, ok := readers[b.Type]
+	if !ok {
+		return nil, fmt.Errorf("backend %q: %w", b.Type, errcode.ErrBackendNotSupported)
+	}
+
+	return r.Read(b, workspace)
+}
+
+// readBackend returns the Backend of the dir, the one initialized
+// by 'terraform init' has priority over the configuration one
+func readBackend(fs afero.Fs, dir string) (Backend, error) {
+	b, err := afero.ReadFile(fs, filepath.Join(dir, ".terraform", "terraform.tfstate"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return Backend{}, err
+	}
+
+	if len(b) != 0 {
+		var init struct {
+			Backend *Backend `json:"backend"`
+		}
+		err = json.Unmarshal(b, &init)
+		if err != nil {
+			return Backend{}, fmt.Errorf("%s: %w", err, errcode.ErrInvalidTFStateFile)
+		}
+		if init.Backend != nil && init.Backend.Type != "" {
+			return *init.Backend, nil
+		}
+	}
+
+	return readConfigBackend(fs, dir)
+}
+
+// readConfigBackend returns the Backend of the 'backend' block
+// of the configuration of the dir, the local one if none
+func readConfigBackend(fs afero.Fs, dir string) (Backend, error) {
+	fis, err := afero.ReadDir(fs, dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return Backend{}, err
+	}
+
+	files := make([]string, 0)
+	for _, fi := range fis {
+		if !fi.IsDir() && (strings.HasSuffix(fi.Name(), ".tf") || strings.HasSuffix(fi.Name(), ".tf.json")) {
+			files = append(files, fi.Name())
+		}
+	}
+	sort.Strings(files)
+
+	parser := hclparse.NewParser()
+	for _, fn := range files {
+		src, err := afero.ReadFile(fs, filepath.Join(dir, fn))
+		if err != nil {
+			return Backend{}, err
+		}
+
+		var (
+			f     *hcl.File
+			diags hcl.Diagnostics
+		)
+		if strings.HasSuffix(fn, ".json") {
+			f, diags = parser.ParseJSON(src, fn)
+		} else {
+			f, diags = parser.ParseHCL(src, fn)
+		}
+		if diags.HasErrors() {
+			return Backend{}, errors.New(diags.Error())
+		}
+
+		content, _, _ := f.Body.PartialContent(&hcl.BodySchema{
+			Blocks: []hcl.BlockHeaderSchema{{Type: "terraform"}},
+		})
+		for _, tb := range content.Blocks {
+			tcontent, _, _ := tb.Body.PartialContent(&hcl.BodySchema{
+				Blocks: []hcl.BlockHeaderSchema{{Type: "backend", LabelNames: []string{"type"}}},
+			})
+			for _, bb := range tcontent.Blocks {
+				attrs, diags := bb.Body.JustAttributes()
+				if diags.HasErrors() {
+					return Backend{}, errors.New(diags.Error())
+				}
+
+				b := Backend{
+					Type:   bb.Labels[0],
+					Config: make(map[string]interface{}, len(attrs)),
+				}
+				// The backends can not use variables
+				// so all the values are known
+				for k, attr := range attrs {
+					val, diags := attr.Expr.Value(nil)
+					if diags.HasErrors() {
+						return Backend{}, errors.New(diags.Error())
+					}
+					b.Config[k], err = ctyInterface(val)
+					if err != nil {
+						return Backend{}, err
+					}
+				}
+
+				return b, nil
+			}
+		}
+	}
+
+	return Backend{Type: BackendLocal}, nil
+}
+
+// ctyInterface returns the v as the
+// value it would have on a JSON
+func ctyInterface(v cty.Value) (interface{}, error) {
+	if v.IsNull() || !v.IsWhollyKnown() {
+		return nil, nil
+	}
+
+	b, err := ctyjson.SimpleJSONValue{Value: v}.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	var i interface{}
+	err = json.Unmarshal(b, &i)
+	if err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
+
+// readLocal reads the TFState of the workspace from
+// the local Backend b of the dir, the default one
+// is on 'terraform.tfstate' and the others
+// on 'terraform.tfstate.d/WORKSPACE/terraform.tfstate'
+func readLocal(fs afero.Fs, dir string, b Backend, workspace string) ([]byte, error) {
+	p := b.String("path")
+	if p == "" {
+		p = "terraform.tfstate"
+	}
+
+	if workspace != DefaultWorkspace {
+		wdir := b.String("workspace_dir")
+		if wdir == "" {
+			wdir = "terraform.tfstate.d"
+		}
+		p = filepath.Join(wdir, workspace, "terraform.tfstate")
+	}
+
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(dir, p)
+	}
+
+	s, err := afero.ReadFile(fs, p)
+	if err != nil {
+		return nil, fmt.Errorf("workspace %q: %w", workspace, err)
+	}
+
+	return s, nil
+}
@@ -0,0 +1,128 @@
+package state_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cycloidio/inframap/errcode"
+	"github.com/cycloidio/inframap/state"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadDir(t *testing.T) {
+	readers := map[string]state.Reader{state.BackendS3: state.S3Reader{}}
+
+	t.Run("SuccessLocal", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "infra/main.tf", []byte(`resource "aws_lb" "front" {}`), 0644))
+		require.NoError(t, afero.WriteFile(fs, "infra/terraform.tfstate", []byte(`{"version": 4}`), 0644))
+
+		b, err := state.ReadDir(fs, "infra", "", readers)
+		require.NoError(t, err)
+		assert.Equal(t, `{"version": 4}`, string(b))
+	})
+	t.Run("SuccessLocalWorkspace", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "infra/terraform.tfstate", []byte(`{"version": 4}`), 0644))
+		require.NoError(t, afero.WriteFile(fs, "infra/terraform.tfstate.d/prod/terraform.tfstate", []byte(`{"version": 4, "serial": 1}`), 0644))
+		require.NoError(t, afero.WriteFile(fs, "infra/terraform.tfstate.d/dev/terraform.tfstate", []byte(`{"version": 4, "serial": 2}`), 0644))
+		require.NoError(t, afero.WriteFile(fs, "infra/.terraform/environment", []byte("dev"), 0644))
+
+		b, err := state.ReadDir(fs, "infra", "prod", readers)
+		require.NoError(t, err)
+		assert.Equal(t, `{"version": 4, "serial": 1}`, string(b))
+
+		// The selected one
+		b, err = state.ReadDir(fs, "infra", "", readers)
+		require.NoError(t, err)
+		assert.Equal(t, `{"version": 4, "serial": 2}`, string(b))
+	})
+	t.Run("SuccessLocalBackend", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "infra/main.tf", []byte(`
+terraform {
+  backend "local" {
+    path          = "states/infra.tfstate"
+    workspace_dir = "workspaces"
+  }
+}
+`), 0644))
+		require.NoError(t, afero.WriteFile(fs, "infra/states/infra.tfstate", []byte(`{"version": 4}`), 0644))
+		require.NoError(t, afero.WriteFile(fs, "infra/workspaces/dev/terraform.tfstate", []byte(`{"version": 4, "serial": 2}`), 0644))
+
+		b, err := state.ReadDir(fs, "infra", "", readers)
+		require.NoError(t, err)
+		assert.Equal(t, `{"version": 4}`, string(b))
+
+		b, err = state.ReadDir(fs, "infra", "dev", readers)
+		require.NoError(t, err)
+		assert.Equal(t, `{"version": 4, "serial": 2}`, string(b))
+	})
+	t.Run("SuccessS3", func(t *testing.T) {
+		// A stand-in of an S3 compatible storage like MinIO
+		objects := map[string]string{
+			"/tfstates/infra/terraform.tfstate":                `{"version": 4}`,
+			"/tfstates/workspaces/dev/infra/terraform.tfstate": `{"version": 4, "serial": 2}`,
+		}
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			o, ok := objects[r.URL.Path]
+			if r.Method != http.MethodGet || !ok {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code></Error>`)
+				return
+			}
+			fmt.Fprint(w, o)
+		}))
+		defer srv.Close()
+
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "infra/main.tf", []byte(`resource "aws_lb" "front" {}`), 0644))
+		require.NoError(t, afero.WriteFile(fs, "infra/.terraform/terraform.tfstate", []byte(fmt.Sprintf(`{
+  "version": 3,
+  "backend": {
+    "type": "s3",
+    "config": {
+      "bucket": "tfstates",
+      "key": "infra/terraform.tfstate",
+      "region": "eu-west-1",
+      "endpoint": %q,
+      "force_path_style": true,
+      "access_key": "minio",
+      "secret_key": "minio123",
+      "workspace_key_prefix": "workspaces",
+      "profile": null
+    },
+    "hash": 123
+  }
+}`, srv.URL)), 0644))
+
+		b, err := state.ReadDir(fs, "infra", "", readers)
+		require.NoError(t, err)
+		assert.Equal(t, `{"version": 4}`, string(b))
+
+		b, err = state.ReadDir(fs, "infra", "dev", readers)
+		require.NoError(t, err)
+		assert.Equal(t, `{"version": 4, "serial": 2}`, string(b))
+
+		_, err = state.ReadDir(fs, "infra", "prod", readers)
+		assert.Error(t, err)
+	})
+	t.Run("ErrBackendNotSupported", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "infra/main.tf", []byte(`
+terraform {
+  backend "gcs" {
+    bucket = "tfstates"
+  }
+}
+`), 0644))
+
+		_, err := state.ReadDir(fs, "infra", "", readers)
+		assert.True(t, errors.Is(err, errcode.ErrBackendNotSupported))
+	})
+}
@@ -0,0 +1,5 @@
+// Package state has the logic to read the TFState
+// of Terraform or OpenTofu into its own model, and to
+// write it back, without depending on the internal
+// one of Terraform
+package state
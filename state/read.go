@@ -0,0 +1,375 @@
+package state
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cycloidio/flatmap"
+	"github.com/cycloidio/inframap/errcode"
+)
+
+// openTofuRegistry is the host of the registry
+// of the providers used by OpenTofu
+const openTofuRegistry = "registry.opentofu.org"
+
+// Read reads the b, the JSON of a TFState of version 3 or 4, into a State.
+// The fields that are not needed are ignored so the TFStates written by
+// newer versions of Terraform, or by OpenTofu, can be read too.
+// If the TFState is encrypted it returns errcode.ErrInvalidTFStateEncrypted
+func Read(b []byte) (*State, error) {
+	if enc := encryption(b); enc != "" {
+		return nil, fmt.Errorf("encrypted with %s: %w", enc, errcode.ErrInvalidTFStateEncrypted)
+	}
+
+	var v struct {
+		Version int `json:"version"`
+	}
+	err := json.Unmarshal(b, &v)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading TFState version %s: %w", err, errcode.ErrInvalidTFStateFile)
+	}
+
+	var s *State
+	switch v.Version {
+	case 3:
+		s, err = readV3(b)
+	case 4:
+		s, err = readV4(b)
+	default:
+		return nil, fmt.Errorf("could not read version %d: %w", v.Version, errcode.ErrInvalidTFStateVersion)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error while reading TFState %s: %w", err, errcode.ErrInvalidTFStateFile)
+	}
+
+	return s, nil
+}
+
+// encryption returns how the b is encrypted,
+// empty if it's not
+func encryption(b []byte) string {
+	tb := bytes.TrimSpace(b)
+	if bytes.HasPrefix(tb, []byte("-----BEGIN PGP")) {
+		return "PGP"
+	}
+	if bytes.HasPrefix(tb, []byte("age-encryption.org/")) || bytes.HasPrefix(tb, []byte("-----BEGIN AGE")) {
+		return "age"
+	}
+
+	var aux struct {
+		EncryptedData     json.RawMessage `json:"encrypted_data"`
+		EncryptionVersion string          `json:"encryption_version"`
+		SOPS              json.RawMessage `json:"sops"`
+	}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return ""
+	}
+
+	if aux.EncryptedData != nil || aux.EncryptionVersion != "" {
+		return "the OpenTofu state encryption"
+	}
+	if aux.SOPS != nil {
+		return "SOPS"
+	}
+
+	return ""
+}
+
+// stateV4 is the format of the TFState since TF 0.12
+type stateV4 struct {
+	Version          int             `json:"version"`
+	TerraformVersion string          `json:"terraform_version"`
+	Serial           uint64          `json:"serial"`
+	Lineage          string          `json:"lineage"`
+	Outputs          json.RawMessage `json:"outputs"`
+	Resources        []resourceV4    `json:"resources"`
+}
+
+type resourceV4 struct {
+	Module    string       `json:"module,omitempty"`
+	Mode      string       `json:"mode"`
+	Type      string       `json:"type"`
+	Name      string       `json:"name"`
+	Each      string       `json:"each,omitempty"`
+	Provider  string       `json:"provider"`
+	Instances []instanceV4 `json:"instances"`
+}
+
+type instanceV4 struct {
+	IndexKey            interface{}       `json:"index_key,omitempty"`
+	Status              string            `json:"status,omitempty"`
+	Deposed             string            `json:"deposed,omitempty"`
+	SchemaVersion       uint64            `json:"schema_version"`
+	Attributes          json.RawMessage   `json:"attributes,omitempty"`
+	AttributesFlat      map[string]string `json:"attributes_flat,omitempty"`
+	SensitiveAttributes json.RawMessage   `json:"sensitive_attributes,omitempty"`
+	Private             string            `json:"private,omitempty"`
+
+	// Dependencies are the ones since TF 0.13
+	// and DependsOn the ones of TF 0.12
+	Dependencies []string `json:"dependencies,omitempty"`
+	DependsOn    []string `json:"depends_on,omitempty"`
+
+	CreateBeforeDestroy bool `json:"create_before_destroy,omitempty"`
+}
+
+func readV4(b []byte) (*State, error) {
+	var sv4 stateV4
+	err := json.Unmarshal(b, &sv4)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &State{
+		Version:          4,
+		TerraformVersion: sv4.TerraformVersion,
+		Serial:           sv4.Serial,
+		Lineage:          sv4.Lineage,
+		Outputs:          sv4.Outputs,
+		Resources:        make([]*Resource, 0, len(sv4.Resources)),
+	}
+
+	for _, rv := range sv4.Resources {
+		r := &Resource{
+			Module:    rv.Module,
+			Mode:      rv.Mode,
+			Type:      rv.Type,
+			Name:      rv.Name,
+			Each:      rv.Each,
+			Provider:  rv.Provider,
+			Instances: make([]*Instance, 0, len(rv.Instances)),
+		}
+
+		if strings.Contains(r.Provider, openTofuRegistry) {
+			s.OpenTofu = true
+		}
+
+		for _, iv := range rv.Instances {
+			// The deposed ones are the old objects of
+			// the instances that are being replaced
+			if iv.Deposed != "" {
+				continue
+			}
+
+			key, err := instanceKey(iv.IndexKey)
+			if err != nil {
+				return nil, fmt.Errorf("resource %q: %w", r.Address(), err)
+			}
+
+			i := &Instance{
+				Key:                 key,
+				Dependencies:        make([]string, 0, len(iv.Dependencies)+len(iv.DependsOn)),
+				SchemaVersion:       iv.SchemaVersion,
+				Status:              iv.Status,
+				Private:             iv.Private,
+				SensitiveAttributes: iv.SensitiveAttributes,
+				CreateBeforeDestroy: iv.CreateBeforeDestroy,
+			}
+
+			if iv.Attributes != nil {
+				err = json.Unmarshal(iv.Attributes, &i.Attributes)
+				if err != nil {
+					return nil, fmt.Errorf("resource %q: %w", r.Address(), err)
+				}
+			} else {
+				i.Attributes = expandFlat(iv.AttributesFlat)
+			}
+
+			// The 'dependencies' are already absolute and the
+			// 'depends_on' are relative to the module in which
+			// the resource is
+			i.Dependencies = append(i.Dependencies, iv.Dependencies...)
+			for _, d := range iv.DependsOn {
+				i.Dependencies = append(i.Dependencies, prefixWithModule(r.Module, d))
+			}
+
+			r.Instances = append(r.Instances, i)
+		}
+
+		s.Resources = append(s.Resources, r)
+	}
+
+	return s, nil
+}
+
+// instanceKey returns the ik as the key of the Instance,
+// the numbers are from 'count' and the strings from 'for_each'
+func instanceKey(ik interface{}) (string, error) {
+	switch k := ik.(type) {
+	case nil:
+		return "", nil
+	case float64:
+		return fmt.Sprintf("[%d]", int(k)), nil
+	case string:
+		return fmt.Sprintf("[%q]", k), nil
+	}
+	return "", fmt.Errorf("invalid index_key %v", ik)
+}
+
+// stateV3 is the format of the TFState of TF 0.11
+type stateV3 struct {
+	TerraformVersion string     `json:"terraform_version"`
+	Serial           uint64     `json:"serial"`
+	Lineage          string     `json:"lineage"`
+	Modules          []moduleV3 `json:"modules"`
+}
+
+type moduleV3 struct {
+	Path      []string              `json:"path"`
+	Resources map[string]resourceV3 `json:"resources"`
+}
+
+type resourceV3 struct {
+	DependsOn []string    `json:"depends_on"`
+	Primary   *instanceV3 `json:"primary"`
+	Provider  string      `json:"provider"`
+}
+
+type instanceV3 struct {
+	ID         string            `json:"id"`
+	Attributes map[string]string `json:"attributes"`
+}
+
+func readV3(b []byte) (*State, error) {
+	var sv3 stateV3
+	err := json.Unmarshal(b, &sv3)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &State{
+		Version:          3,
+		TerraformVersion: sv3.TerraformVersion,
+		Serial:           sv3.Serial,
+		Lineage:          sv3.Lineage,
+		Resources:        make([]*Resource, 0),
+	}
+
+	for _, m := range sv3.Modules {
+		// The path is like ["root", "app", "db"]
+		// and the root is not part of the address
+		var module string
+		if len(m.Path) > 1 {
+			module = "module." + strings.Join(m.Path[1:], ".module.")
+		}
+
+		keys := make([]string, 0, len(m.Resources))
+		for k := range m.Resources {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		// resources holds as key the address of the
+		// resource, as all the instances of it are
+		// on different keys ('aws_instance.web.0')
+		resources := make(map[string]*Resource)
+		for _, k := range keys {
+			rv := m.Resources[k]
+			if rv.Primary == nil {
+				continue
+			}
+
+			mode, rtype, name, key, err := parseKeyV3(k)
+			if err != nil {
+				return nil, err
+			}
+
+			r, ok := resources[mode+rtype+name]
+			if !ok {
+				r = &Resource{
+					Module:    module,
+					Mode:      mode,
+					Type:      rtype,
+					Name:      name,
+					Provider:  rv.Provider,
+					Instances: make([]*Instance, 0, 1),
+				}
+				resources[mode+rtype+name] = r
+				s.Resources = append(s.Resources, r)
+			}
+
+			i := &Instance{
+				Key:          key,
+				Attributes:   expandFlat(rv.Primary.Attributes),
+				Dependencies: make([]string, 0, len(rv.DependsOn)),
+			}
+			if _, ok := i.Attributes["id"]; !ok && rv.Primary.ID != "" {
+				i.Attributes["id"] = rv.Primary.ID
+			}
+
+			// The 'depends_on' are relative to the module and
+			// could reference all the instances 'aws_instance.web.*'
+			for _, d := range rv.DependsOn {
+				ds := strings.Split(d, ".")
+				if len(ds) > 2 && ds[0] == ModeData {
+					ds = ds[:3]
+				} else if len(ds) > 2 {
+					ds = ds[:2]
+				}
+				i.Dependencies = append(i.Dependencies, prefixWithModule(module, strings.Join(ds, ".")))
+			}
+
+			r.Instances = append(r.Instances, i)
+		}
+	}
+
+	return s, nil
+}
+
+// parseKeyV3 parses the k, that is like 'aws_instance.web',
+// 'aws_instance.web.0' or 'data.aws_ami.ubuntu'
+func parseKeyV3(k string) (mode, rtype, name, key string, err error) {
+	mode = ModeManaged
+	parts := strings.Split(k, ".")
+	if parts[0] == ModeData {
+		mode = ModeData
+		parts = parts[1:]
+	}
+
+	if len(parts) < 2 {
+		return "", "", "", "", fmt.Errorf("invalid resource %q", k)
+	}
+
+	if len(parts) > 2 {
+		i, err := strconv.Atoi(parts[2])
+		if err != nil {
+			return "", "", "", "", fmt.Errorf("invalid resource %q: %w", k, err)
+		}
+		key = fmt.Sprintf("[%d]", i)
+	}
+
+	return mode, parts[0], parts[1], key, nil
+}
+
+// expandFlat expands the flat attributes, that are flatmap, to
+// the actual structure by expanding each one of the unique keys
+func expandFlat(flat map[string]string) map[string]interface{} {
+	keys := make(map[string]struct{})
+	for k := range flat {
+		kk := strings.Split(k, ".")
+		keys[kk[0]] = struct{}{}
+	}
+
+	res := make(map[string]interface{}, len(keys))
+	for k := range keys {
+		res[k] = flatmap.Expand(flat, k)
+	}
+
+	return res
+}
+
+// prefixWithModule prefixes the addr, that is relative to
+// the module, with the module if it's not the root one, so
+// 'module.db.aws_db_instance.x' inside of 'module.app' is
+// 'module.app.module.db.aws_db_instance.x'. Some TFStates
+// already have them with the module so those are kept
+func prefixWithModule(module, addr string) string {
+	if module == "" || strings.HasPrefix(addr, module+".") {
+		return addr
+	}
+	return fmt.Sprintf("%s.%s", module, addr)
+}
@@ -0,0 +1,203 @@
+package state_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/cycloidio/inframap/errcode"
+	"github.com/cycloidio/inframap/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRead(t *testing.T) {
+	t.Run("SuccessOpenTofu", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/opentofu_state.json")
+		require.NoError(t, err)
+
+		s, err := state.Read(src)
+		require.NoError(t, err)
+
+		provider := `provider["registry.opentofu.org/hashicorp/aws"]`
+		es := &state.State{
+			Version:          4,
+			TerraformVersion: "1.8.0",
+			OpenTofu:         true,
+			Serial:           5,
+			Lineage:          "a5f0e7a2-7c1b-4c43-9bd4-1c9c4f3a1e2b",
+			Outputs:          json.RawMessage(`{}`),
+			Resources: []*state.Resource{
+				{
+					Mode:     state.ModeData,
+					Type:     "aws_ami",
+					Name:     "ubuntu",
+					Provider: provider,
+					Instances: []*state.Instance{
+						{
+							Attributes:   map[string]interface{}{"id": "ami-123"},
+							Dependencies: []string{},
+						},
+					},
+				},
+				{
+					Mode:     state.ModeManaged,
+					Type:     "aws_security_group",
+					Name:     "front",
+					Provider: provider,
+					Instances: []*state.Instance{
+						{
+							Attributes: map[string]interface{}{
+								"id":   "sg-123",
+								"tags": map[string]interface{}{"Name": "front"},
+							},
+							Dependencies:        []string{},
+							SchemaVersion:       1,
+							SensitiveAttributes: json.RawMessage(`[]`),
+						},
+					},
+				},
+				{
+					Module:   "module.app",
+					Mode:     state.ModeManaged,
+					Type:     "aws_instance",
+					Name:     "web",
+					Each:     "list",
+					Provider: provider,
+					Instances: []*state.Instance{
+						{
+							Key:           "[0]",
+							Attributes:    map[string]interface{}{"id": "i-0"},
+							Dependencies:  []string{"aws_security_group.front"},
+							SchemaVersion: 1,
+						},
+						{
+							Key:           "[1]",
+							Attributes:    map[string]interface{}{"id": "i-1"},
+							Dependencies:  []string{"aws_security_group.front"},
+							SchemaVersion: 1,
+						},
+					},
+				},
+				{
+					Module:   "module.app",
+					Mode:     state.ModeManaged,
+					Type:     "aws_ebs_volume",
+					Name:     "data",
+					Each:     "map",
+					Provider: provider,
+					Instances: []*state.Instance{
+						{
+							Key: `["eu"]`,
+							Attributes: map[string]interface{}{
+								"id":   "vol-eu",
+								"tags": map[string]interface{}{"Name": "eu"},
+							},
+							Dependencies: []string{"module.app.aws_instance.web"},
+						},
+					},
+				},
+			},
+		}
+
+		assert.Equal(t, es, s)
+		assert.Equal(t, "data.aws_ami.ubuntu", s.Resources[0].Address())
+		assert.Equal(t, "aws_instance.web", s.Resources[2].Address())
+	})
+	t.Run("SuccessVersion3", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/version_3_state.json")
+		require.NoError(t, err)
+
+		s, err := state.Read(src)
+		require.NoError(t, err)
+
+		es := &state.State{
+			Version:          3,
+			TerraformVersion: "0.11.14",
+			Serial:           3,
+			Lineage:          "3fd5eb87-d6b9-8b47-6c2d-7209f7f884ee",
+			Resources: []*state.Resource{
+				{
+					Mode:     state.ModeManaged,
+					Type:     "aws_security_group",
+					Name:     "front",
+					Provider: "provider.aws",
+					Instances: []*state.Instance{
+						{
+							Attributes: map[string]interface{}{
+								"id":   "sg-123",
+								"tags": map[string]interface{}{"Name": "front"},
+							},
+							Dependencies: []string{},
+						},
+					},
+				},
+				{
+					Module:   "module.app",
+					Mode:     state.ModeManaged,
+					Type:     "aws_instance",
+					Name:     "web",
+					Provider: "provider.aws",
+					Instances: []*state.Instance{
+						{
+							Key:          "[0]",
+							Attributes:   map[string]interface{}{"id": "i-0", "ami": "ami-123"},
+							Dependencies: []string{"module.app.aws_security_group.front", "module.app.data.aws_ami.ubuntu"},
+						},
+						{
+							Key:          "[1]",
+							Attributes:   map[string]interface{}{"id": "i-1", "ami": "ami-123"},
+							Dependencies: []string{"module.app.aws_security_group.front", "module.app.data.aws_ami.ubuntu"},
+						},
+					},
+				},
+				{
+					Module:   "module.app",
+					Mode:     state.ModeData,
+					Type:     "aws_ami",
+					Name:     "ubuntu",
+					Provider: "provider.aws",
+					Instances: []*state.Instance{
+						{
+							Attributes:   map[string]interface{}{"id": "ami-123"},
+							Dependencies: []string{},
+						},
+					},
+				},
+			},
+		}
+
+		assert.Equal(t, es, s)
+	})
+	t.Run("SuccessDependsOnNestedModule", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/version_4_depends_on_state.json")
+		require.NoError(t, err)
+
+		s, err := state.Read(src)
+		require.NoError(t, err)
+
+		require.Len(t, s.Resources, 2)
+		assert.Equal(t, []string{"module.app.aws_security_group.front", "module.app.module.db.aws_db_instance.main"}, s.Resources[0].Instances[0].Dependencies)
+		assert.Equal(t, []string{}, s.Resources[1].Instances[0].Dependencies)
+	})
+	t.Run("ErrInvalidTFStateEncrypted", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/opentofu_encrypted_state.json")
+		require.NoError(t, err)
+
+		_, err = state.Read(src)
+		assert.True(t, errors.Is(err, errcode.ErrInvalidTFStateEncrypted))
+		assert.Contains(t, err.Error(), "OpenTofu")
+
+		_, err = state.Read([]byte("-----BEGIN PGP MESSAGE-----\n\nhQEMA\n-----END PGP MESSAGE-----"))
+		assert.True(t, errors.Is(err, errcode.ErrInvalidTFStateEncrypted))
+	})
+	t.Run("ErrInvalidTFStateVersion", func(t *testing.T) {
+		_, err := state.Read([]byte(`{"version": 2}`))
+		assert.True(t, errors.Is(err, errcode.ErrInvalidTFStateVersion))
+	})
+	t.Run("ErrInvalidTFStateFile", func(t *testing.T) {
+		_, err := state.Read([]byte(`{"version": 4, "resources": {}}`))
+		assert.True(t, errors.Is(err, errcode.ErrInvalidTFStateFile))
+	})
+}
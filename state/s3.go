@@ -0,0 +1,77 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// S3Reader is the Reader of the 's3' Backend, it can
+// also be used with the S3 compatible ones (like MinIO)
+// with the 'endpoint' and 'force_path_style'
+type S3Reader struct{}
+
+// Read reads the TFState of the workspace from the bucket of the b, the
+// ones of the workspaces that are not the default are on
+// 'WORKSPACE_KEY_PREFIX/WORKSPACE/KEY' as Terraform does
+func (S3Reader) Read(b Backend, workspace string) ([]byte, error) {
+	ctx := context.Background()
+
+	key := b.String("key")
+	if workspace != DefaultWorkspace {
+		prefix := b.String("workspace_key_prefix")
+		if prefix == "" {
+			prefix = "env:"
+		}
+		key = path.Join(prefix, workspace, key)
+	}
+
+	region := b.String("region")
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	opts := []func(*config.LoadOptions) error{config.WithRegion(region)}
+	if p := b.String("profile"); p != "" {
+		opts = append(opts, config.WithSharedConfigProfile(p))
+	}
+	if ak, sk := b.String("access_key"), b.String("secret_key"); ak != "" && sk != "" {
+		opts = append(opts, config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(ak, sk, b.String("token"))))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("could not load the AWS config: %w", err)
+	}
+
+	// The newer versions of Terraform have the
+	// 'endpoints.s3' and 'use_path_style' instead
+	endpoint := b.String("endpoint")
+	if es, ok := b.Config["endpoints"].(map[string]interface{}); ok && endpoint == "" {
+		endpoint, _ = es["s3"].(string)
+	}
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		o.UsePathStyle = b.Bool("force_path_style") || b.Bool("use_path_style")
+		if endpoint != "" {
+			o.EndpointResolver = s3.EndpointResolverFromURL(endpoint)
+		}
+	})
+
+	out, err := client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(b.String("bucket")),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not get %q from the bucket %q: %w", key, b.String("bucket"), err)
+	}
+	defer out.Body.Close()
+
+	return ioutil.ReadAll(out.Body)
+}
@@ -0,0 +1,113 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// List of the Modes that a Resource can have
+const (
+	ModeManaged = "managed"
+	ModeData    = "data"
+)
+
+// State is the content of a TFState
+type State struct {
+	// Version it's the version of the format
+	// of the TFState, 3 or 4
+	Version int
+
+	// TerraformVersion it's the version of Terraform
+	// (or OpenTofu) that wrote the TFState
+	TerraformVersion string
+
+	// OpenTofu is true if the TFState
+	// has been written by OpenTofu
+	OpenTofu bool
+
+	// Serial and Lineage identify the TFState
+	// and each one of the versions it had
+	Serial  uint64
+	Lineage string
+
+	// Outputs are the outputs of the root module as
+	// they are on the TFState, only of the version 4
+	Outputs json.RawMessage
+
+	// Resources are the resources of
+	// all the modules of the TFState
+	Resources []*Resource
+}
+
+// Resource is a resource of the State
+// with all the instances it has
+type Resource struct {
+	// Module it's the path of the module in which the
+	// resource is, like 'module.app' or 'module.app[0]',
+	// empty if it's on the root module
+	Module string
+
+	// Mode it's ModeManaged or ModeData
+	Mode string
+
+	// Type it's the type of the resource 'aws_lb'
+	Type string
+
+	// Name it's the name of the resource 'front'
+	Name string
+
+	// Each it's 'list' or 'map' if the resource has 'count'
+	// or 'for_each', only on the TFStates of TF 0.12
+	Each string
+
+	// Provider it's the provider configuration used, like
+	// 'provider["registry.terraform.io/hashicorp/aws"]'
+	Provider string
+
+	// Instances are the instances of the resource, more
+	// than one if it has 'count' or 'for_each'
+	Instances []*Instance
+}
+
+// Instance is an instance of a Resource
+type Instance struct {
+	// Key it's the key of the instance like '[0]' or
+	// '["eu"]', empty if the resource does not have
+	// 'count' or 'for_each'
+	Key string
+
+	// Attributes are the attributes of the instance, the
+	// flat ones of the old versions are already expanded
+	Attributes map[string]interface{}
+
+	// Dependencies are the addresses of the resources it
+	// depends on, with the module, like 'module.app.aws_lb.front'
+	Dependencies []string
+
+	// SchemaVersion it's the version of the schema
+	// of the resource used on the Attributes
+	SchemaVersion uint64
+
+	// Status it's 'tainted' if the instance is
+	Status string
+
+	// Private it's the data of the provider, encoded in base64,
+	// and SensitiveAttributes the paths of the sensitive ones
+	Private             string
+	SensitiveAttributes json.RawMessage
+
+	// CreateBeforeDestroy it's the lifecycle of the
+	// resource used when it has to be replaced
+	CreateBeforeDestroy bool
+}
+
+// Address returns the address of the resource
+// without the module, like 'aws_lb.front'
+// or 'data.aws_ami.ubuntu'
+func (r *Resource) Address() string {
+	addr := fmt.Sprintf("%s.%s", r.Type, r.Name)
+	if r.Mode == ModeData {
+		addr = fmt.Sprintf("%s.%s", ModeData, addr)
+	}
+	return addr
+}
@@ -0,0 +1,97 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Write writes the s as the JSON of a TFState of version 4,
+// the format used since TF 0.12, so the TFStates of version
+// 3 are upgraded. Only the fields of the State are written
+func Write(s *State) ([]byte, error) {
+	outputs := s.Outputs
+	if outputs == nil {
+		outputs = json.RawMessage(`{}`)
+	}
+
+	sv4 := stateV4{
+		Version:          4,
+		TerraformVersion: s.TerraformVersion,
+		Serial:           s.Serial,
+		Lineage:          s.Lineage,
+		Outputs:          outputs,
+		Resources:        make([]resourceV4, 0, len(s.Resources)),
+	}
+
+	for _, r := range s.Resources {
+		rv := resourceV4{
+			Module:    r.Module,
+			Mode:      r.Mode,
+			Type:      r.Type,
+			Name:      r.Name,
+			Each:      r.Each,
+			Provider:  r.Provider,
+			Instances: make([]instanceV4, 0, len(r.Instances)),
+		}
+
+		for _, i := range r.Instances {
+			ik, err := indexKey(i.Key)
+			if err != nil {
+				return nil, fmt.Errorf("resource %q: %w", r.Address(), err)
+			}
+
+			attrs := i.Attributes
+			if attrs == nil {
+				attrs = make(map[string]interface{})
+			}
+			b, err := json.Marshal(attrs)
+			if err != nil {
+				return nil, fmt.Errorf("resource %q: %w", r.Address(), err)
+			}
+
+			rv.Instances = append(rv.Instances, instanceV4{
+				IndexKey:            ik,
+				Status:              i.Status,
+				SchemaVersion:       i.SchemaVersion,
+				Attributes:          b,
+				SensitiveAttributes: i.SensitiveAttributes,
+				Private:             i.Private,
+				Dependencies:        i.Dependencies,
+				CreateBeforeDestroy: i.CreateBeforeDestroy,
+			})
+		}
+
+		sv4.Resources = append(sv4.Resources, rv)
+	}
+
+	return json.MarshalIndent(sv4, "", "  ")
+}
+
+// indexKey returns the k, the key of the Instance, as the
+// 'index_key', it's the opposite of instanceKey
+func indexKey(k string) (interface{}, error) {
+	if k == "" {
+		return nil, nil
+	}
+
+	if !strings.HasPrefix(k, "[") || !strings.HasSuffix(k, "]") {
+		return nil, fmt.Errorf("invalid key %q", k)
+	}
+	k = k[1 : len(k)-1]
+
+	if strings.HasPrefix(k, `"`) {
+		s, err := strconv.Unquote(k)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %w", k, err)
+		}
+		return s, nil
+	}
+
+	i, err := strconv.Atoi(k)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key %q: %w", k, err)
+	}
+	return i, nil
+}
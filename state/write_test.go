@@ -0,0 +1,58 @@
+package state_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/cycloidio/inframap/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/opentofu_state.json")
+		require.NoError(t, err)
+
+		es, err := state.Read(src)
+		require.NoError(t, err)
+
+		b, err := state.Write(es)
+		require.NoError(t, err)
+
+		s, err := state.Read(b)
+		require.NoError(t, err)
+
+		assert.Equal(t, es, s)
+	})
+	t.Run("SuccessVersion4", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/version_4_state.json")
+		require.NoError(t, err)
+
+		s, err := state.Read(src)
+		require.NoError(t, err)
+
+		b, err := state.Write(s)
+		require.NoError(t, err)
+
+		assert.JSONEq(t, string(src), string(b))
+	})
+	t.Run("SuccessVersion3", func(t *testing.T) {
+		src, err := ioutil.ReadFile("./testdata/version_3_state.json")
+		require.NoError(t, err)
+
+		es, err := state.Read(src)
+		require.NoError(t, err)
+
+		b, err := state.Write(es)
+		require.NoError(t, err)
+
+		s, err := state.Read(b)
+		require.NoError(t, err)
+
+		es.Version = 4
+		es.Outputs = json.RawMessage(`{}`)
+		assert.Equal(t, es, s)
+	})
+}